@@ -0,0 +1,160 @@
+package decimal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DivisionScale is the number of fractional digits kept when a quotient
+// does not terminate within the operands' own scale.
+const DivisionScale = 16
+
+var ten = big.NewInt(10)
+
+// Decimal is an exact base-10 number, unscaled × 10^-scale. The scale is
+// kept through addition and multiplication, so 1.10 + 2.20 is 3.30.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the unscaled value of d expressed with the given,
+// larger or equal, scale.
+func (d Decimal) rescale(scale int) *big.Int {
+	return new(big.Int).Mul(d.unscaled, pow10(scale-d.scale))
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return New(new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale)
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return New(new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale)
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return New(new(big.Int).Mul(d.unscaled, other.unscaled), d.scale+other.scale)
+}
+
+// Quo divides d by a non-zero other, rounding half to even at
+// DivisionScale digits and then dropping trailing zeros down to the larger
+// operand scale.
+func (d Decimal) Quo(other Decimal) Decimal {
+	scale := max(d.scale, other.scale, DivisionScale)
+	numerator := new(big.Int).Mul(d.unscaled, pow10(other.scale+scale))
+	denominator := new(big.Int).Mul(other.unscaled, pow10(d.scale))
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	if c := twice.Cmp(new(big.Int).Abs(denominator)); c > 0 || (c == 0 && quotient.Bit(0) == 1) {
+		if numerator.Sign()*denominator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	result := New(quotient, scale)
+	minScale := max(d.scale, other.scale)
+	for result.scale > minScale {
+		q, r := new(big.Int).QuoRem(result.unscaled, ten, new(big.Int))
+		if r.Sign() != 0 {
+			break
+		}
+		result = New(q, result.scale-1)
+	}
+	return result
+}
+
+// Rem returns the remainder of truncated division, taking the sign of d.
+func (d Decimal) Rem(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return New(new(big.Int).Rem(d.rescale(scale), other.rescale(scale)), scale)
+}
+
+func (d Decimal) Neg() Decimal {
+	return New(new(big.Int).Neg(d.unscaled), d.scale)
+}
+
+func (d Decimal) Sign() int {
+	return d.unscaled.Sign()
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// BitLen estimates the bits d takes at full precision: those of its
+// unscaled value plus those of the power of ten it is scaled by, at about
+// 3.32 bits per digit.
+func (d Decimal) BitLen() int {
+	return d.unscaled.BitLen() + (d.scale*3322+999)/1000
+}
+
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.unscaled, pow10(d.scale))
+}
+
+// Int truncates d towards zero.
+func (d Decimal) Int() *big.Int {
+	return new(big.Int).Quo(d.unscaled, pow10(d.scale))
+}
+
+func (d Decimal) Float64() float64 {
+	value, _ := d.Rat().Float64()
+	return value
+}
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+func New(unscaled *big.Int, scale int) Decimal {
+	return Decimal{
+		unscaled: unscaled,
+		scale:    scale,
+	}
+}
+
+func FromInt(value *big.Int) Decimal {
+	return New(new(big.Int).Set(value), 0)
+}
+
+// Parse reads an optionally signed decimal such as "-12.50".
+func Parse(text string) (Decimal, error) {
+	body := strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+	if len(text)-len(body) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", text)
+	}
+
+	whole, fraction, _ := strings.Cut(body, ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", text)
+	}
+
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if strings.HasPrefix(text, "-") {
+		unscaled.Neg(unscaled)
+	}
+	return New(unscaled, len(fraction)), nil
+}
@@ -0,0 +1,120 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func mustParse(t *testing.T, text string) Decimal {
+	t.Helper()
+	d, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", text, err)
+	}
+	return d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"0", "0"},
+		{"42", "42"},
+		{"12.50", "12.50"},
+		{"-12.50", "-12.50"},
+		{"+3", "3"},
+		{"-0.05", "-0.05"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"007", "7"},
+		{"123456789012345678901234567890.1", "123456789012345678901234567890.1"},
+	}
+	for _, test := range tests {
+		got := mustParse(t, test.text).String()
+		if got != test.want {
+			t.Errorf("Parse(%q) = %s, want %s", test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, text := range []string{"", "-", "+", ".", "--1", "+-1", "1.2.3", "abc", "1e5", " 1"} {
+		_, err := Parse(text)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestQuo(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"10", "4", "2.5"},
+		{"1", "4", "0.25"},
+		{"1.10", "1", "1.10"},
+		{"1", "3", "0.3333333333333333"},
+		{"2", "3", "0.6666666666666667"},
+		{"-2", "3", "-0.6666666666666667"},
+		{"2", "-3", "-0.6666666666666667"},
+		{"-2", "-3", "0.6666666666666667"},
+		// Ties at the 17th digit round to the even neighbour.
+		{"1", "20000000000000000", "0"},
+		{"3", "20000000000000000", "0.0000000000000002"},
+		{"-3", "20000000000000000", "-0.0000000000000002"},
+		{"5", "20000000000000000", "0.0000000000000002"},
+		{"-5", "20000000000000000", "-0.0000000000000002"},
+	}
+	for _, test := range tests {
+		got := mustParse(t, test.a).Quo(mustParse(t, test.b)).String()
+		if got != test.want {
+			t.Errorf("%s / %s = %s, want %s", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestRem(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"7", "3", "1"},
+		{"-7", "3", "-1"},
+		{"7", "-3", "1"},
+		{"-7", "-3", "-1"},
+		{"5.5", "2", "1.5"},
+		{"1", "0.3", "0.1"},
+		{"6", "3", "0"},
+	}
+	for _, test := range tests {
+		got := mustParse(t, test.a).Rem(mustParse(t, test.b)).String()
+		if got != test.want {
+			t.Errorf("%s %% %s = %s, want %s", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		unscaled int64
+		scale    int
+		want     string
+	}{
+		{0, 0, "0"},
+		{0, 2, "0.00"},
+		{5, 2, "0.05"},
+		{-1, 3, "-0.001"},
+		{-5, 1, "-0.5"},
+		{100, 2, "1.00"},
+		{12345, 2, "123.45"},
+		{-12345, 0, "-12345"},
+	}
+	for _, test := range tests {
+		got := New(big.NewInt(test.unscaled), test.scale).String()
+		if got != test.want {
+			t.Errorf("New(%d, %d) = %s, want %s", test.unscaled, test.scale, got, test.want)
+		}
+	}
+}
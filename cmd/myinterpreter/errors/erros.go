@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
 )
@@ -20,13 +21,65 @@ func New(message string) Error {
 	}
 }
 
+// StackFrame records a call to Function made from Line of its caller. File
+// names the module the function's body is in, and is empty for the script
+// being run. A frame without a Function is the top-level code of the module
+// File, run by an import on Line.
+type StackFrame struct {
+	Function string
+	Line     int
+	File     string
+}
+
+func (sf StackFrame) name() string {
+	if sf.Function == "" {
+		return "module"
+	}
+	return sf.Function + "()"
+}
+
+// location renders line, prefixed by the module it is in, if any.
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("[line %d]", line)
+	}
+	return fmt.Sprintf("[%s, line %d]", file, line)
+}
+
 type RuntimeError struct {
 	Token   token.Token
 	Message string
+	// Stack holds the calls that were active when the error was raised,
+	// innermost first. It is empty for errors raised in top-level code.
+	Stack []StackFrame
+	// Thrown is set for errors raised by a throw statement, which carry the
+	// thrown Lox value in Value.
+	Thrown bool
+	Value  interface{}
 }
 
 func (err RuntimeError) Error() string {
-	return fmt.Sprintf("%s\n[line %d]", err.Message, err.Token.Line)
+	file := ""
+	if len(err.Stack) > 0 {
+		file = err.Stack[0].File
+	}
+	return fmt.Sprintf("%s\n%s", err.Message, location(file, err.Token.Line))
+}
+
+// Traceback renders the message followed by the line reached in every
+// active function and module, innermost first, ending with the top-level
+// script.
+func (err RuntimeError) Traceback() string {
+	var sb strings.Builder
+	sb.WriteString(err.Message)
+
+	line := err.Token.Line
+	for _, frame := range err.Stack {
+		fmt.Fprintf(&sb, "\n%s in %s", location(frame.File, line), frame.name())
+		line = frame.Line
+	}
+	fmt.Fprintf(&sb, "\n[line %d] in script", line)
+	return sb.String()
 }
 
 func NewRuntimeError(token token.Token, message string) RuntimeError {
@@ -36,6 +89,15 @@ func NewRuntimeError(token token.Token, message string) RuntimeError {
 	}
 }
 
+func NewThrownError(token token.Token, message string, value interface{}) RuntimeError {
+	return RuntimeError{
+		Message: message,
+		Token:   token,
+		Thrown:  true,
+		Value:   value,
+	}
+}
+
 type LexicalError struct {
 	Message string
 	Line    int
@@ -71,3 +133,70 @@ func NewParseError(t token.Token, message string, code int) error {
 		Code:    code,
 	}
 }
+
+type LimitError struct {
+	Limit   string
+	Message string
+}
+
+func (le LimitError) Error() string {
+	return le.Message
+}
+
+func NewLimitError(limit string, message string) error {
+	return LimitError{
+		Limit:   limit,
+		Message: message,
+	}
+}
+
+// StackOverflowError stops a script whose calls nest deeper than the
+// interpreter can follow without exhausting the Go stack. Like LimitError
+// it is not a RuntimeError, so catch clauses don't intercept it.
+type StackOverflowError struct {
+	Token token.Token
+}
+
+func (soe StackOverflowError) Error() string {
+	return fmt.Sprintf("Stack overflow.\n[line %d]", soe.Token.Line)
+}
+
+func NewStackOverflowError(t token.Token) error {
+	return StackOverflowError{
+		Token: t,
+	}
+}
+
+type CancellationError struct {
+	Cause error
+}
+
+func (ce CancellationError) Error() string {
+	return fmt.Sprintf("Execution cancelled: %v.", ce.Cause)
+}
+
+func (ce CancellationError) Unwrap() error {
+	return ce.Cause
+}
+
+func NewCancellationError(cause error) error {
+	return CancellationError{
+		Cause: cause,
+	}
+}
+
+// ExitError stops a script that called os.exit. It is not a RuntimeError,
+// so catch clauses don't intercept it.
+type ExitError struct {
+	Code int
+}
+
+func (ee ExitError) Error() string {
+	return fmt.Sprintf("Exited with status %d.", ee.Code)
+}
+
+func NewExitError(code int) error {
+	return ExitError{
+		Code: code,
+	}
+}
@@ -112,3 +112,224 @@ func NewUnary(operator token.Token, right interfaces.Expr) UnaryExpr {
 		Right:    right,
 	}
 }
+
+type LogicalExpr struct {
+	Left     interfaces.Expr
+	Right    interfaces.Expr
+	Operator token.Token
+}
+
+func (le LogicalExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitLogicalExpr(le)
+}
+
+func NewLogical(left interfaces.Expr, operator token.Token, right interfaces.Expr) LogicalExpr {
+	return LogicalExpr{
+		Left:     left,
+		Right:    right,
+		Operator: operator,
+	}
+}
+
+type ConditionalExpr struct {
+	Condition  interfaces.Expr
+	Question   token.Token
+	ThenBranch interfaces.Expr
+	ElseBranch interfaces.Expr
+}
+
+func (ce ConditionalExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitConditionalExpr(ce)
+}
+
+func NewConditionalExpr(condition interfaces.Expr, question token.Token, thenBranch interfaces.Expr, elseBranch interfaces.Expr) ConditionalExpr {
+	return ConditionalExpr{
+		Condition:  condition,
+		Question:   question,
+		ThenBranch: thenBranch,
+		ElseBranch: elseBranch,
+	}
+}
+
+type CommaExpr struct {
+	Expressions []interfaces.Expr
+}
+
+func (ce CommaExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitCommaExpr(ce)
+}
+
+func NewCommaExpr(expressions []interfaces.Expr) CommaExpr {
+	return CommaExpr{
+		Expressions: expressions,
+	}
+}
+
+// FunctionExpr is an anonymous function, written fun (a) { ... } or as the
+// arrow shorthand (a) => a. Keyword is the fun or => token.
+type FunctionExpr struct {
+	Keyword token.Token
+	Params  []token.Token
+	Body    []interfaces.Statement
+}
+
+func (fe FunctionExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitFunctionExpr(fe)
+}
+
+func NewFunctionExpr(keyword token.Token, params []token.Token, body []interfaces.Statement) FunctionExpr {
+	return FunctionExpr{
+		Keyword: keyword,
+		Params:  params,
+		Body:    body,
+	}
+}
+
+type CallExpr struct {
+	Callee    interfaces.Expr
+	Paren     token.Token
+	Arguments []interfaces.Expr
+}
+
+func (ce CallExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitCallExpr(ce)
+}
+
+func NewCallExpr(callee interfaces.Expr, paren token.Token, arguments []interfaces.Expr) CallExpr {
+	return CallExpr{
+		Callee:    callee,
+		Paren:     paren,
+		Arguments: arguments,
+	}
+}
+
+type GetExpr struct {
+	Object interfaces.Expr
+	Name   token.Token
+}
+
+func (ge GetExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitGetExpr(ge)
+}
+
+func NewGetExpr(object interfaces.Expr, name token.Token) GetExpr {
+	return GetExpr{
+		Object: object,
+		Name:   name,
+	}
+}
+
+type ListExpr struct {
+	Bracket  token.Token
+	Elements []interfaces.Expr
+}
+
+func (le ListExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitListExpr(le)
+}
+
+func NewListExpr(bracket token.Token, elements []interfaces.Expr) ListExpr {
+	return ListExpr{
+		Bracket:  bracket,
+		Elements: elements,
+	}
+}
+
+// MapExpr holds the entries of a map literal in source order, the key of
+// each entry at the same position in Keys as its value in Values.
+type MapExpr struct {
+	Brace  token.Token
+	Keys   []interfaces.Expr
+	Values []interfaces.Expr
+}
+
+func (me MapExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitMapExpr(me)
+}
+
+func NewMapExpr(brace token.Token, keys []interfaces.Expr, values []interfaces.Expr) MapExpr {
+	return MapExpr{
+		Brace:  brace,
+		Keys:   keys,
+		Values: values,
+	}
+}
+
+type IndexExpr struct {
+	Object  interfaces.Expr
+	Bracket token.Token
+	Index   interfaces.Expr
+}
+
+func (ie IndexExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitIndexExpr(ie)
+}
+
+func NewIndexExpr(object interfaces.Expr, bracket token.Token, index interfaces.Expr) IndexExpr {
+	return IndexExpr{
+		Object:  object,
+		Bracket: bracket,
+		Index:   index,
+	}
+}
+
+// IndexSetExpr is object[index] = value. For a compound assignment such as
+// object[index] += value, Operator is the binary operator to apply to the
+// current element; it is the zero Token for a plain assignment.
+type IndexSetExpr struct {
+	Object   interfaces.Expr
+	Bracket  token.Token
+	Index    interfaces.Expr
+	Value    interfaces.Expr
+	Operator token.Token
+}
+
+func (ise IndexSetExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitIndexSetExpr(ise)
+}
+
+func NewIndexSetExpr(object interfaces.Expr, bracket token.Token, index interfaces.Expr, value interfaces.Expr) IndexSetExpr {
+	return IndexSetExpr{
+		Object:  object,
+		Bracket: bracket,
+		Index:   index,
+		Value:   value,
+	}
+}
+
+// SliceExpr is object[Start:End]; either bound may be nil when omitted.
+type SliceExpr struct {
+	Object  interfaces.Expr
+	Bracket token.Token
+	Start   interfaces.Expr
+	End     interfaces.Expr
+}
+
+func (se SliceExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitSliceExpr(se)
+}
+
+func NewSliceExpr(object interfaces.Expr, bracket token.Token, start interfaces.Expr, end interfaces.Expr) SliceExpr {
+	return SliceExpr{
+		Object:  object,
+		Bracket: bracket,
+		Start:   start,
+		End:     end,
+	}
+}
+
+// InterpolationExpr concatenates the stringified values of its parts,
+// which alternate between literal segments and embedded expressions.
+type InterpolationExpr struct {
+	Parts []interfaces.Expr
+}
+
+func (ie InterpolationExpr) Accept(v interfaces.Visitor) (interface{}, error) {
+	return v.VisitInterpolationExpr(ie)
+}
+
+func NewInterpolationExpr(parts []interfaces.Expr) InterpolationExpr {
+	return InterpolationExpr{
+		Parts: parts,
+	}
+}
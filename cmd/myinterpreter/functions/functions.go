@@ -2,7 +2,10 @@ package functions
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
 )
 
 func TypeOf(obj interface{}) string {
@@ -15,6 +18,12 @@ func TypeOf(obj interface{}) string {
 		return "bool"
 	case float64:
 		return "float64"
+	case int64:
+		return "int64"
+	case *big.Int:
+		return "bigint"
+	case decimal.Decimal:
+		return "decimal"
 	case string:
 		return "string"
 	}
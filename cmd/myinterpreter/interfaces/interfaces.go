@@ -11,6 +11,18 @@ type Visitor interface {
 	VisitUnaryExpr(u Expr) (interface{}, error)
 	VisitVarExpr(v Expr) (interface{}, error)
 	VisitAssignExpr(ae Expr) (interface{}, error)
+	VisitLogicalExpr(le Expr) (interface{}, error)
+	VisitConditionalExpr(ce Expr) (interface{}, error)
+	VisitCommaExpr(ce Expr) (interface{}, error)
+	VisitFunctionExpr(fe Expr) (interface{}, error)
+	VisitCallExpr(ce Expr) (interface{}, error)
+	VisitGetExpr(ge Expr) (interface{}, error)
+	VisitListExpr(le Expr) (interface{}, error)
+	VisitMapExpr(me Expr) (interface{}, error)
+	VisitInterpolationExpr(ie Expr) (interface{}, error)
+	VisitIndexExpr(ie Expr) (interface{}, error)
+	VisitIndexSetExpr(ise Expr) (interface{}, error)
+	VisitSliceExpr(se Expr) (interface{}, error)
 }
 
 type Statement interface {
@@ -23,4 +35,15 @@ type StatementVisitor interface {
 	VisitPrintStatement(printStmt Statement) (interface{}, error)
 	VisitVarStatement(varStmt Statement) (interface{}, error)
 	VisitBlockStatement(blockStmt Statement) (interface{}, error)
+	VisitIfStatement(ifStmt Statement) (interface{}, error)
+	VisitWhileStatement(whileStmt Statement) (interface{}, error)
+	VisitForInStatement(forInStmt Statement) (interface{}, error)
+	VisitFunctionStatement(funStmt Statement) (interface{}, error)
+	VisitReturnStatement(returnStmt Statement) (interface{}, error)
+	VisitThrowStatement(throwStmt Statement) (interface{}, error)
+	VisitTryStatement(tryStmt Statement) (interface{}, error)
+	VisitBreakStatement(breakStmt Statement) (interface{}, error)
+	VisitImportStatement(importStmt Statement) (interface{}, error)
+	VisitExportStatement(exportStmt Statement) (interface{}, error)
+	VisitContinueStatement(continueStmt Statement) (interface{}, error)
 }
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/interfaces"
@@ -12,6 +15,15 @@ import (
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/visitor"
 )
 
+// traceback selects printing runtime errors with their full call stack
+// instead of the single "[line N]" format the codecrafters tester expects.
+var traceback = false
+
+// integers selects scanning number literals without a decimal point as
+// integers. It is off by default because the codecrafters tester expects
+// every number to be a float64.
+var integers = false
+
 func main() {
 	// You can use print statements as follows for debugging, they'll be visible when running tests.
 	fmt.Fprintln(os.Stderr, "Logs from your program will appear here!")
@@ -22,7 +34,50 @@ func main() {
 	}
 
 	command := os.Args[1]
-	filename := os.Args[2]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	maxSteps := flags.Int("max-steps", 0, "maximum number of statements to execute (0 for no limit)")
+	timeout := flags.Duration("timeout", 0, "maximum wall-clock run time, e.g. 5s (0 for no limit)")
+	maxCallDepth := flags.Int("max-call-depth", 0, "maximum function call depth (0 for no limit)")
+	maxHeap := flags.Uint64("max-heap", 0, "approximate maximum heap growth in bytes (0 for no limit)")
+	var allowedDirs []string
+	flags.Func("allow-dir", "directory the io module may access (repeatable; none by default)", func(dir string) error {
+		allowedDirs = append(allowedDirs, dir)
+		return nil
+	})
+	flags.BoolVar(&traceback, "traceback", false, "print runtime errors with a stack trace instead of the single-line format")
+	sandbox := flags.Bool("sandbox", false, "disable environment variables and subprocesses in the os module")
+	flags.BoolVar(&integers, "integers", false, "scan number literals without a decimal point as integers instead of floats")
+	flags.Parse(os.Args[2:])
+
+	if flags.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: ./your_program.sh tokenize <filename>")
+		os.Exit(1)
+	}
+
+	limits := visitor.Limits{
+		MaxSteps:     *maxSteps,
+		Timeout:      *timeout,
+		MaxCallDepth: *maxCallDepth,
+		MaxHeapBytes: *maxHeap,
+	}
+
+	capabilities := visitor.AllCapabilities
+	if *sandbox {
+		capabilities.Env = false
+		capabilities.Exec = false
+	}
+	opts := options{
+		limits:       limits,
+		allowedDirs:  allowedDirs,
+		capabilities: capabilities,
+		args:         flags.Args()[1:],
+	}
+
+	if command == "test" {
+		os.Exit(runTests(flags.Arg(0), opts))
+	}
+
+	filename := flags.Arg(0)
 	fileContents, err := os.ReadFile(filename)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
@@ -36,7 +91,7 @@ func main() {
 	} else if command == "evaluate" {
 		evaluate(fileContents)
 	} else if command == "run" {
-		run(fileContents)
+		run(filename, fileContents, opts)
 	} else {
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 	}
@@ -50,7 +105,14 @@ func printErrorsAndExit(errs []error, code int) {
 }
 
 func printErrorAndExit(err error) {
-	fmt.Fprintln(os.Stderr, err.Error())
+	if exitError, ok := err.(errors.ExitError); ok {
+		os.Exit(exitError.Code)
+	}
+	if runtimeError, ok := err.(errors.RuntimeError); ok && traceback {
+		fmt.Fprintln(os.Stderr, runtimeError.Traceback())
+	} else {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
 	switch err := err.(type) {
 	case errors.LexicalError:
 		os.Exit(65)
@@ -58,6 +120,12 @@ func printErrorAndExit(err error) {
 		os.Exit(err.Code)
 	case errors.RuntimeError:
 		os.Exit(70)
+	case errors.LimitError:
+		os.Exit(75)
+	case errors.StackOverflowError:
+		os.Exit(76)
+	case errors.CancellationError:
+		os.Exit(130)
 	default:
 		os.Exit(1)
 	}
@@ -111,13 +179,41 @@ func evaluate(fileContents []byte) {
 	fmt.Println(interpreter.Stringify(value))
 }
 
-func run(fileContents []byte) {
+// options configures the interpreters of the run and test commands.
+type options struct {
+	limits       visitor.Limits
+	allowedDirs  []string
+	capabilities visitor.Capabilities
+	// args are the command-line arguments after the script name, which
+	// scripts read with os.args().
+	args []string
+}
+
+func newInterpreter(filename string, opts options) visitor.Interpreter {
+	interpreter := visitor.NewInterpreter()
+	interpreter.SetLimits(opts.limits)
+	interpreter.SetScriptPath(filename)
+	interpreter.SetIntegers(integers)
+	interpreter.SetCapabilities(opts.capabilities)
+	interpreter.SetArgs(opts.args)
+	err := interpreter.SetAllowedDirs(opts.allowedDirs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error allowing directory: %v\n", err)
+		os.Exit(1)
+	}
+	return interpreter
+}
+
+func run(filename string, fileContents []byte, opts options) {
 	statements, err := parseStatement(tokenize(fileContents, true))
 	if err != nil {
 		printErrorAndExit(err)
 	}
-	interpreter := visitor.NewInterpreter()
-	err = interpreter.Interpret(statements)
+	interpreter := newInterpreter(filename, opts)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = interpreter.InterpretContext(ctx, statements)
 	if err != nil {
 		printErrorAndExit(err)
 	}
@@ -135,6 +231,7 @@ func parseStatement(t []token.Token) ([]interfaces.Statement, error) {
 
 func scantokens(filecontents []byte) (scanner.Scanner, []error) {
 	s := scanner.NewScanner(string(filecontents))
+	s.Integers = integers
 	err := s.ScanTokens()
 	if err != nil {
 		return s, err
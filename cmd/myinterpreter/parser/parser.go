@@ -9,8 +9,10 @@ import (
 )
 
 type Parser struct {
-	Tokens  []token.Token
-	Current int
+	Tokens        []token.Token
+	Current       int
+	functionDepth int
+	loopDepth     int
 }
 
 func (p *Parser) error(t token.Token, message string) error {
@@ -44,6 +46,11 @@ func (p *Parser) check(tokentype token.TokenType) bool {
 	return !p.isAtEnd() && p.peek().TokenType == tokentype
 }
 
+func (p *Parser) checkAhead(offset int, tokentype token.TokenType) bool {
+	index := p.Current + offset
+	return index < len(p.Tokens) && p.Tokens[index].TokenType == tokentype
+}
+
 func (p *Parser) match(tokentypes ...token.TokenType) bool {
 	for _, tokentype := range tokentypes {
 		if p.check(tokentype) {
@@ -70,9 +77,22 @@ func (p *Parser) primary() (interfaces.Expr, error) {
 		return expr.NewLiteral(nil), nil
 	} else if p.match(token.NUMBER, token.STRING) {
 		return expr.NewLiteral(p.previous().Literal), nil
+	} else if p.match(token.INTERPOLATION) {
+		return p.interpolation()
 	} else if p.match(token.IDENTIFIER) {
 		return expr.NewVarExpr(p.previous()), nil
+	} else if p.match(token.LEFT_BRACKET) {
+		return p.list()
+	} else if p.match(token.LEFT_BRACE) {
+		// A brace in statement position starts a block, so one reaching an
+		// expression is always a map literal.
+		return p.mapLiteral()
+	} else if p.match(token.FUN) {
+		return p.lambda()
 	} else if p.match(token.LEFT_PAREN) {
+		if p.isArrow() {
+			return p.arrow()
+		}
 		expression, err := p.Expression()
 		if err != nil {
 			return nil, err
@@ -86,8 +106,92 @@ func (p *Parser) primary() (interfaces.Expr, error) {
 	return nil, p.error(p.peek(), "Expect expression.")
 }
 
+func (p *Parser) interpolation() (interfaces.Expr, error) {
+	var parts []interfaces.Expr
+
+	for {
+		if segment := p.previous().Literal.(string); segment != "" {
+			parts = append(parts, expr.NewLiteral(segment))
+		}
+		part, err := p.Expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+
+		if p.match(token.INTERPOLATION) {
+			continue
+		}
+		_, err = p.consume(token.STRING, "Expect '}' after interpolated expression.", 65)
+		if err != nil {
+			return nil, err
+		}
+		if segment := p.previous().Literal.(string); segment != "" {
+			parts = append(parts, expr.NewLiteral(segment))
+		}
+		return expr.NewInterpolationExpr(parts), nil
+	}
+}
+
+func (p *Parser) list() (interfaces.Expr, error) {
+	bracket := p.previous()
+	var elements []interfaces.Expr
+
+	if !p.check(token.RIGHT_BRACKET) {
+		for {
+			element, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACKET, "Expect ']' after list elements.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewListExpr(bracket, elements), nil
+}
+
+func (p *Parser) mapLiteral() (interfaces.Expr, error) {
+	brace := p.previous()
+	var keys, values []interfaces.Expr
+
+	if !p.check(token.RIGHT_BRACE) {
+		for {
+			key, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.consume(token.COLON, "Expect ':' after map key.", 65)
+			if err != nil {
+				return nil, err
+			}
+			value, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACE, "Expect '}' after map entries.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewMapExpr(brace, keys, values), nil
+}
+
 func (p *Parser) unary() (interfaces.Expr, error) {
-	if p.match(token.BANG, token.MINUS) {
+	if p.match(token.BANG, token.MINUS, token.TILDE) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -96,11 +200,182 @@ func (p *Parser) unary() (interfaces.Expr, error) {
 		return expr.NewUnary(operator, right), nil
 	}
 
-	primary, err := p.primary()
+	return p.power()
+}
+
+// power binds tighter than unary operators on its left, so -2 ** 2 is -4,
+// and is right-associative because its exponent is parsed as a unary.
+func (p *Parser) power() (interfaces.Expr, error) {
+	expression, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.STAR_STAR) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewBinary(expression, operator, right)
+	}
+	return expression, nil
+}
+
+func (p *Parser) bitOr() (interfaces.Expr, error) {
+	expression, err := p.bitXor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.PIPE) {
+		operator := p.previous()
+		right, err := p.bitXor()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewBinary(expression, operator, right)
+	}
+	return expression, nil
+}
+
+func (p *Parser) bitXor() (interfaces.Expr, error) {
+	expression, err := p.bitAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.CARET) {
+		operator := p.previous()
+		right, err := p.bitAnd()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewBinary(expression, operator, right)
+	}
+	return expression, nil
+}
+
+func (p *Parser) bitAnd() (interfaces.Expr, error) {
+	expression, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.AMPERSAND) {
+		operator := p.previous()
+		right, err := p.shift()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewBinary(expression, operator, right)
+	}
+	return expression, nil
+}
+
+func (p *Parser) shift() (interfaces.Expr, error) {
+	expression, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.LESS_LESS, token.GREATER_GREATER) {
+		operator := p.previous()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewBinary(expression, operator, right)
+	}
+	return expression, nil
+}
+
+func (p *Parser) finishCall(callee interfaces.Expr) (interfaces.Expr, error) {
+	var arguments []interfaces.Expr
+
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			if len(arguments) >= 255 {
+				return nil, p.error(p.peek(), "Can't have more than 255 arguments.")
+			}
+			argument, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	paren, err := p.consume(token.RIGHT_PAREN, "Expect ')' after arguments.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewCallExpr(callee, paren, arguments), nil
+}
+
+func (p *Parser) finishIndex(object interfaces.Expr) (interfaces.Expr, error) {
+	var start interfaces.Expr
+	var err error
+	if !p.check(token.COLON) {
+		start, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.match(token.COLON) {
+		var end interfaces.Expr
+		if !p.check(token.RIGHT_BRACKET) {
+			end, err = p.Expression()
+			if err != nil {
+				return nil, err
+			}
+		}
+		bracket, err := p.consume(token.RIGHT_BRACKET, "Expect ']' after slice.", 65)
+		if err != nil {
+			return nil, err
+		}
+		return expr.NewSliceExpr(object, bracket, start, end), nil
+	}
+
+	bracket, err := p.consume(token.RIGHT_BRACKET, "Expect ']' after index.", 65)
 	if err != nil {
 		return nil, err
 	}
-	return primary, nil
+	return expr.NewIndexExpr(object, bracket, start), nil
+}
+
+func (p *Parser) call() (interfaces.Expr, error) {
+	expression, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.match(token.LEFT_PAREN) {
+			expression, err = p.finishCall(expression)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(token.DOT) {
+			name, err := p.consume(token.IDENTIFIER, "Expect property name after '.'.", 65)
+			if err != nil {
+				return nil, err
+			}
+			expression = expr.NewGetExpr(expression, name)
+		} else if p.match(token.LEFT_BRACKET) {
+			expression, err = p.finishIndex(expression)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			break
+		}
+	}
+	return expression, nil
 }
 
 func (p *Parser) factor() (interfaces.Expr, error) {
@@ -109,7 +384,7 @@ func (p *Parser) factor() (interfaces.Expr, error) {
 		return nil, err
 	}
 
-	for p.match(token.SLASH, token.STAR) {
+	for p.match(token.SLASH, token.STAR, token.PERCENT) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -138,14 +413,14 @@ func (p *Parser) term() (interfaces.Expr, error) {
 }
 
 func (p *Parser) comparsion() (interfaces.Expr, error) {
-	expression, err := p.term()
+	expression, err := p.bitOr()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL) {
 		operator := p.previous()
-		right, err := p.term()
+		right, err := p.bitOr()
 		if err != nil {
 			return nil, err
 		}
@@ -155,29 +430,136 @@ func (p *Parser) comparsion() (interfaces.Expr, error) {
 }
 
 func (p *Parser) Expression() (interfaces.Expr, error) {
-	return p.assignment()
+	return p.comma()
+}
+
+// comma parses the C-style sequence a, b, which evaluates both operands
+// and yields the last. Argument, element and initializer lists parse
+// assignment instead so their commas keep separating items.
+func (p *Parser) comma() (interfaces.Expr, error) {
+	expression, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(token.COMMA) {
+		return expression, nil
+	}
+
+	expressions := []interfaces.Expr{expression}
+	for p.match(token.COMMA) {
+		next, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, next)
+	}
+	return expr.NewCommaExpr(expressions), nil
+}
+
+// ternary parses cond ? a : b. It is right-associative and binds looser
+// than or but tighter than assignment, so x = c ? a : b assigns the result.
+func (p *Parser) ternary() (interfaces.Expr, error) {
+	condition, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.QUESTION) {
+		question := p.previous()
+		thenBranch, err := p.Expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.COLON, "Expect ':' after then branch of conditional expression.", 65)
+		if err != nil {
+			return nil, err
+		}
+		elseBranch, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		return expr.NewConditionalExpr(condition, question, thenBranch, elseBranch), nil
+	}
+	return condition, nil
+}
+
+// compoundOperators maps each compound assignment to the binary operator
+// it applies.
+var compoundOperators = map[token.TokenType]token.Token{
+	token.PLUS_EQUAL:  token.NewToken(token.PLUS, "+", nil, 0),
+	token.MINUS_EQUAL: token.NewToken(token.MINUS, "-", nil, 0),
+	token.STAR_EQUAL:  token.NewToken(token.STAR, "*", nil, 0),
+	token.SLASH_EQUAL: token.NewToken(token.SLASH, "/", nil, 0),
 }
 
 func (p *Parser) assignment() (interfaces.Expr, error) {
-	expression, err := p.equality()
+	expression, err := p.ternary()
 	if err != nil {
 		return nil, err
 	}
 
-	if p.match(token.EQUAL) {
+	if p.match(token.EQUAL, token.PLUS_EQUAL, token.MINUS_EQUAL, token.STAR_EQUAL, token.SLASH_EQUAL) {
 		equals := p.previous()
 		value, err := p.assignment()
 		if err != nil {
 			return nil, err
 		}
 
+		operator, isCompound := compoundOperators[equals.TokenType]
+		operator.Line = equals.Line
+
 		switch expression := expression.(type) {
 		case expr.VarExpr:
-			name := expression.Token
-			return expr.NewAssignExpr(name, value), nil
+			// a += b is desugared to a = a + b.
+			if isCompound {
+				value = expr.NewBinary(expression, operator, value)
+			}
+			return expr.NewAssignExpr(expression.Token, value), nil
+		case expr.IndexExpr:
+			// The object and index must only be evaluated once, so the
+			// operator is applied by the interpreter instead.
+			indexSet := expr.NewIndexSetExpr(expression.Object, expression.Bracket, expression.Index, value)
+			if isCompound {
+				indexSet.Operator = operator
+			}
+			return indexSet, nil
+		}
+
+		return nil, p.error(equals, "Invalid assignment target.")
+	}
+	return expression, nil
+}
+
+func (p *Parser) or() (interfaces.Expr, error) {
+	expression, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.OR) {
+		operator := p.previous()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
 		}
+		expression = expr.NewLogical(expression, operator, right)
+	}
+	return expression, nil
+}
 
-		p.error(equals, "Invalid assignment target.")
+func (p *Parser) and() (interfaces.Expr, error) {
+	expression, err := p.equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.AND) {
+		operator := p.previous()
+		right, err := p.equality()
+		if err != nil {
+			return nil, err
+		}
+		expression = expr.NewLogical(expression, operator, right)
 	}
 	return expression, nil
 }
@@ -224,11 +606,304 @@ func (p *Parser) printStatement() (interfaces.Statement, error) {
 	return statements.NewPrintStatement(value), nil
 }
 
+func (p *Parser) ifStatement() (interfaces.Statement, error) {
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'if'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after if condition.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	thenBranch, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	var elseBranch interfaces.Statement
+	if p.match(token.ELSE) {
+		elseBranch, err = p.statement()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statements.NewIfStatement(condition, thenBranch, elseBranch), nil
+}
+
+func (p *Parser) loopBody() (interfaces.Statement, error) {
+	p.loopDepth++
+	defer func() {
+		p.loopDepth--
+	}()
+	return p.statement()
+}
+
+func (p *Parser) breakStatement() (interfaces.Statement, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		return nil, p.error(keyword, "Can't use 'break' outside of a loop.")
+	}
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after 'break'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewBreakStatement(keyword), nil
+}
+
+func (p *Parser) continueStatement() (interfaces.Statement, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		return nil, p.error(keyword, "Can't use 'continue' outside of a loop.")
+	}
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after 'continue'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewContinueStatement(keyword), nil
+}
+
+func (p *Parser) whileStatement() (interfaces.Statement, error) {
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'while'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after condition.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody()
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewWhileStatement(condition, body, nil), nil
+}
+
+func (p *Parser) forInStatement() (interfaces.Statement, error) {
+	name, err := p.consume(token.IDENTIFIER, "Expect variable name.", 65)
+	if err != nil {
+		return nil, err
+	}
+	keyword, err := p.consume(token.IN, "Expect 'in' after loop variable.", 65)
+	if err != nil {
+		return nil, err
+	}
+	iterable, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after for-in clause.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody()
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewForInStatement(name, keyword, iterable, body), nil
+}
+
+func (p *Parser) forStatement() (interfaces.Statement, error) {
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'for'.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.check(token.VAR) && p.checkAhead(2, token.IN) {
+		p.advance()
+		return p.forInStatement()
+	}
+
+	var initializer interfaces.Statement
+	if p.match(token.SEMICOLON) {
+		initializer = nil
+	} else if p.match(token.VAR) {
+		initializer, err = p.varDeclaration()
+	} else {
+		initializer, err = p.expressionStatement()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var condition interfaces.Expr
+	if !p.check(token.SEMICOLON) {
+		condition, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after loop condition.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	var increment interfaces.Expr
+	if !p.check(token.RIGHT_PAREN) {
+		increment, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses.", 65)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody()
+	if err != nil {
+		return nil, err
+	}
+
+	if condition == nil {
+		condition = expr.NewLiteral(true)
+	}
+	body = statements.NewWhileStatement(condition, body, increment)
+	if initializer != nil {
+		body = statements.NewBlockStatement([]interfaces.Statement{initializer, body})
+	}
+	return body, nil
+}
+
+func (p *Parser) returnStatement() (interfaces.Statement, error) {
+	keyword := p.previous()
+	if p.functionDepth == 0 {
+		return nil, p.error(keyword, "Can't return from top-level code.")
+	}
+
+	var value interfaces.Expr
+	var err error
+	if !p.check(token.SEMICOLON) {
+		value, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after return value.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewReturnStatement(keyword, value), nil
+}
+
+func (p *Parser) throwStatement() (interfaces.Statement, error) {
+	keyword := p.previous()
+	value, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after thrown value.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewThrowStatement(keyword, value), nil
+}
+
+func (p *Parser) tryStatement() (interfaces.Statement, error) {
+	_, err := p.consume(token.LEFT_BRACE, "Expect '{' after 'try'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	var catchName token.Token
+	var catchBody []interfaces.Statement
+	if p.match(token.CATCH) {
+		_, err = p.consume(token.LEFT_PAREN, "Expect '(' after 'catch'.", 65)
+		if err != nil {
+			return nil, err
+		}
+		catchName, err = p.consume(token.IDENTIFIER, "Expect exception variable name.", 65)
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after exception variable.", 65)
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.LEFT_BRACE, "Expect '{' after catch clause.", 65)
+		if err != nil {
+			return nil, err
+		}
+		catchBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+		if catchBody == nil {
+			catchBody = []interfaces.Statement{}
+		}
+	}
+
+	var finallyBody []interfaces.Statement
+	if p.match(token.FINALLY) {
+		_, err = p.consume(token.LEFT_BRACE, "Expect '{' after 'finally'.", 65)
+		if err != nil {
+			return nil, err
+		}
+		finallyBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+		if finallyBody == nil {
+			finallyBody = []interfaces.Statement{}
+		}
+	}
+
+	if catchBody == nil && finallyBody == nil {
+		return nil, p.error(p.peek(), "Expect 'catch' or 'finally' after try block.")
+	}
+	return statements.NewTryStatement(body, catchName, catchBody, finallyBody), nil
+}
+
 func (p *Parser) statement() (interfaces.Statement, error) {
+	if p.match(token.BREAK) {
+		return p.breakStatement()
+	}
+
+	if p.match(token.CONTINUE) {
+		return p.continueStatement()
+	}
+
+	if p.match(token.FOR) {
+		return p.forStatement()
+	}
+
+	if p.match(token.IF) {
+		return p.ifStatement()
+	}
+
 	if p.match(token.PRINT) {
 		return p.printStatement()
 	}
 
+	if p.match(token.RETURN) {
+		return p.returnStatement()
+	}
+
+	if p.match(token.THROW) {
+		return p.throwStatement()
+	}
+
+	if p.match(token.TRY) {
+		return p.tryStatement()
+	}
+
+	if p.match(token.WHILE) {
+		return p.whileStatement()
+	}
+
 	if p.match(token.LEFT_BRACE) {
 		stmts, err := p.block()
 		return statements.NewBlockStatement(stmts), err
@@ -253,13 +928,14 @@ func (p *Parser) block() ([]interfaces.Statement, error) {
 }
 
 func (p *Parser) varDeclaration() (interfaces.Statement, error) {
+	doc := p.previous().Doc
 	name, err := p.consume(token.IDENTIFIER, "Expect variable name.", 70)
 	if err != nil {
 		return nil, err
 	}
 	var initializer interfaces.Expr
 	if p.match(token.EQUAL) {
-		initializer, err = p.Expression()
+		initializer, err = p.assignment()
 		if err != nil {
 			return nil, err
 		}
@@ -270,10 +946,211 @@ func (p *Parser) varDeclaration() (interfaces.Statement, error) {
 		return nil, err
 	}
 
-	return statements.NewVarStatement(name, initializer), nil
+	statement := statements.NewVarStatement(name, initializer)
+	statement.Doc = doc
+	return statement, nil
+}
+
+func (p *Parser) function(kind string) (interfaces.Statement, error) {
+	doc := p.previous().Doc
+	name, err := p.consume(token.IDENTIFIER, "Expect "+kind+" name.", 65)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.LEFT_PAREN, "Expect '(' after "+kind+" name.", 65)
+	if err != nil {
+		return nil, err
+	}
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before "+kind+" body.", 65)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.functionBody(p.block)
+	if err != nil {
+		return nil, err
+	}
+	statement := statements.NewFunctionStatement(name, params, body)
+	statement.Doc = doc
+	return statement, nil
+}
+
+// parameters parses a parameter list up to and including the closing ')'.
+func (p *Parser) parameters() ([]token.Token, error) {
+	var params []token.Token
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			if len(params) >= 255 {
+				return nil, p.error(p.peek(), "Can't have more than 255 parameters.")
+			}
+			param, err := p.consume(token.IDENTIFIER, "Expect parameter name.", 65)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+	_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after parameters.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// functionBody parses a body with body inside a new function scope, where
+// return is allowed and break and continue no longer reach enclosing loops.
+func (p *Parser) functionBody(body func() ([]interfaces.Statement, error)) ([]interfaces.Statement, error) {
+	enclosingLoopDepth := p.loopDepth
+	p.functionDepth++
+	p.loopDepth = 0
+	defer func() {
+		p.functionDepth--
+		p.loopDepth = enclosingLoopDepth
+	}()
+	return body()
+}
+
+// lambda parses fun (a, b) { ... } once the fun keyword has been matched.
+func (p *Parser) lambda() (interfaces.Expr, error) {
+	keyword := p.previous()
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'fun'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before function body.", 65)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.functionBody(p.block)
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewFunctionExpr(keyword, params, body), nil
+}
+
+// isArrow reports whether the '(' just matched opens the parameter list of
+// an arrow function, so (a, b) => a + b is told apart from a grouping.
+func (p *Parser) isArrow() bool {
+	offset := 0
+	if !p.checkAhead(offset, token.RIGHT_PAREN) {
+		for {
+			if !p.checkAhead(offset, token.IDENTIFIER) {
+				return false
+			}
+			offset++
+			if !p.checkAhead(offset, token.COMMA) {
+				break
+			}
+			offset++
+		}
+	}
+	return p.checkAhead(offset, token.RIGHT_PAREN) && p.checkAhead(offset+1, token.ARROW)
+}
+
+// arrow parses (a, b) => expression, whose body returns the expression.
+func (p *Parser) arrow() (interfaces.Expr, error) {
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	arrow, err := p.consume(token.ARROW, "Expect '=>' after parameters.", 65)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.functionBody(func() ([]interfaces.Statement, error) {
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		return []interfaces.Statement{statements.NewReturnStatement(arrow, value)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expr.NewFunctionExpr(arrow, params, body), nil
+}
+
+// importStatement parses import "path" as name; once import is matched.
+func (p *Parser) importStatement() (interfaces.Statement, error) {
+	keyword := p.previous()
+	path, err := p.consume(token.STRING, "Expect module path string after 'import'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.AS, "Expect 'as' after module path.", 65)
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.consume(token.IDENTIFIER, "Expect module name after 'as'.", 65)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after import.", 65)
+	if err != nil {
+		return nil, err
+	}
+	return statements.NewImportStatement(keyword, path, name), nil
+}
+
+// exportDeclaration parses the var or fun declaration following export,
+// which Parse only accepts at the top level of a module.
+func (p *Parser) exportDeclaration() (interfaces.Statement, error) {
+	keyword := p.previous()
+	var declaration interfaces.Statement
+	var err error
+	var name token.Token
+	if p.match(token.FUN) {
+		declaration, err = p.function("function")
+		if err != nil {
+			return nil, err
+		}
+		function := declaration.(statements.FunctionStatement)
+		if function.Doc == "" {
+			function.Doc = keyword.Doc
+		}
+		name, declaration = function.Name, function
+	} else if p.match(token.VAR) {
+		declaration, err = p.varDeclaration()
+		if err != nil {
+			return nil, err
+		}
+		variable := declaration.(statements.VarStatement)
+		if variable.Doc == "" {
+			variable.Doc = keyword.Doc
+		}
+		name, declaration = variable.Name, variable
+	} else {
+		return nil, p.error(p.peek(), "Expect 'var' or 'fun' after 'export'.")
+	}
+	return statements.NewExportStatement(keyword, name, declaration), nil
 }
 
 func (p *Parser) decalration() (interfaces.Statement, error) {
+	if p.match(token.EXPORT) {
+		return nil, p.error(p.previous(), "Can only export top-level declarations.")
+	}
+
+	if p.match(token.IMPORT) {
+		return p.importStatement()
+	}
+
+	// fun followed by '(' is an anonymous function in an expression statement.
+	if !p.checkAhead(1, token.LEFT_PAREN) && p.match(token.FUN) {
+		return p.function("function")
+	}
+
 	if p.match(token.VAR) {
 		return p.varDeclaration()
 	}
@@ -285,7 +1162,13 @@ func (p *Parser) Parse() ([]interfaces.Statement, error) {
 	var statements []interfaces.Statement
 
 	for !p.isAtEnd() {
-		statement, err := p.decalration()
+		var statement interfaces.Statement
+		var err error
+		if p.match(token.EXPORT) {
+			statement, err = p.exportDeclaration()
+		} else {
+			statement, err = p.decalration()
+		}
 		if err != nil {
 			return statements, err
 		}
@@ -2,8 +2,12 @@ package scanner
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/functions"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
@@ -15,6 +19,13 @@ type Scanner struct {
 	Source       string
 	StartIndex   int
 	Tokens       []token.Token
+	// Integers scans number literals without a decimal point as int64
+	// instead of float64.
+	Integers bool
+	// interpolations holds the brace nesting depth inside each open "${".
+	interpolations []int
+	// doc collects "///" comment lines until the next token is added.
+	doc []string
 }
 
 func (s *Scanner) isAtEnd() bool {
@@ -27,6 +38,10 @@ func (s *Scanner) getCurrentSubString() string {
 
 func (s *Scanner) addToken(tokentype token.TokenType, literal interface{}) {
 	newToken := token.NewToken(tokentype, s.getCurrentSubString(), literal, s.Line)
+	if len(s.doc) > 0 {
+		newToken.Doc = strings.Join(s.doc, "\n")
+		s.doc = nil
+	}
 	s.Tokens = append(s.Tokens, newToken)
 }
 
@@ -62,18 +77,34 @@ func (s *Scanner) checkReservedIdentifiers() bool {
 	switch s.getCurrentSubString() {
 	case "and":
 		s.addToken(token.AND, nil)
+	case "as":
+		s.addToken(token.AS, nil)
+	case "break":
+		s.addToken(token.BREAK, nil)
+	case "catch":
+		s.addToken(token.CATCH, nil)
 	case "class":
 		s.addToken(token.CLASS, nil)
+	case "continue":
+		s.addToken(token.CONTINUE, nil)
 	case "else":
 		s.addToken(token.ELSE, nil)
+	case "export":
+		s.addToken(token.EXPORT, nil)
 	case "false":
 		s.addToken(token.FALSE, nil)
+	case "finally":
+		s.addToken(token.FINALLY, nil)
 	case "for":
 		s.addToken(token.FOR, nil)
 	case "fun":
 		s.addToken(token.FUN, nil)
 	case "if":
 		s.addToken(token.IF, nil)
+	case "import":
+		s.addToken(token.IMPORT, nil)
+	case "in":
+		s.addToken(token.IN, nil)
 	case "nil":
 		s.addToken(token.NIL, nil)
 	case "or":
@@ -86,8 +117,12 @@ func (s *Scanner) checkReservedIdentifiers() bool {
 		s.addToken(token.SUPER, nil)
 	case "this":
 		s.addToken(token.THIS, nil)
+	case "throw":
+		s.addToken(token.THROW, nil)
 	case "true":
 		s.addToken(token.TRUE, nil)
+	case "try":
+		s.addToken(token.TRY, nil)
 	case "var":
 		s.addToken(token.VAR, nil)
 	case "while":
@@ -107,38 +142,174 @@ func (s *Scanner) parseIdentifier() {
 	}
 }
 
-func (s *Scanner) parseNumber() {
+func (s *Scanner) parseNumber() error {
 	for ; functions.IsDigit(s.peek()) && !s.isAtEnd(); s.advance() {
 	}
 
+	isFloat := false
 	if s.peek() == '.' && functions.IsDigit(s.peekNext()) {
+		isFloat = true
 		s.advance()
 		for ; functions.IsDigit(s.peek()) && !s.isAtEnd(); s.advance() {
 		}
 	}
 
+	// A trailing n marks a big integer and a trailing d a decimal.
+	digits := s.getCurrentSubString()
+	suffix := s.peek()
+	if (suffix == 'n' && !isFloat || suffix == 'd') && !functions.IsAlphaDigit(s.peekNext()) {
+		s.advance()
+		if suffix == 'n' {
+			value, _ := new(big.Int).SetString(digits, 10)
+			s.addToken(token.NUMBER, value)
+			return nil
+		}
+		value, err := decimal.Parse(digits)
+		if err != nil {
+			return errors.NewLexicalError(s.Line, "", "Invalid decimal literal.")
+		}
+		s.addToken(token.NUMBER, value)
+		return nil
+	}
+
+	// Integer literals too large for an int64 become big integers, as the
+	// results of integer arithmetic do.
+	if s.Integers && !isFloat {
+		value, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			integer, _ := new(big.Int).SetString(digits, 10)
+			s.addToken(token.NUMBER, integer)
+			return nil
+		}
+		s.addToken(token.NUMBER, value)
+		return nil
+	}
+
 	value, err := strconv.ParseFloat(s.getCurrentSubString(), 64)
 	if err != nil {
 		panic(err)
 	}
 	s.addToken(token.NUMBER, value)
+	return nil
+}
+
+// parseEscape decodes the escape sequence following a backslash. The raw
+// text stays in the token's lexeme; only the literal is decoded.
+func (s *Scanner) parseEscape() (string, error) {
+	char := s.advance()
+	switch char {
+	case 'n':
+		return "\n", nil
+	case 't':
+		return "\t", nil
+	case 'r':
+		return "\r", nil
+	case '"':
+		return "\"", nil
+	case '$':
+		return "$", nil
+	case '\\':
+		return "\\", nil
+	case 'u':
+		return s.parseUnicodeEscape()
+	}
+	message := fmt.Sprintf("Invalid escape sequence: \\%c.", char)
+	return "", errors.NewLexicalError(s.Line, "", message)
+}
+
+func (s *Scanner) parseUnicodeEscape() (string, error) {
+	invalid := errors.NewLexicalError(s.Line, "", "Invalid Unicode escape sequence.")
+	if !s.match('{') {
+		return "", invalid
+	}
+
+	var digits strings.Builder
+	for !s.isAtEnd() && s.peek() != '}' && s.peek() != '"' {
+		digits.WriteRune(s.advance())
+	}
+	if !s.match('}') || digits.Len() == 0 || digits.Len() > 6 {
+		return "", invalid
+	}
+
+	codePoint, err := strconv.ParseUint(digits.String(), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(codePoint)) {
+		return "", invalid
+	}
+	return string(rune(codePoint)), nil
 }
 
 func (s *Scanner) parseString() error {
+	var result strings.Builder
+	var escapeErr error
+
 	for !s.isAtEnd() && s.peek() != '"' {
-		if s.peek() == '\n' {
+		char := s.advance()
+		if char == '\n' {
 			s.Line++
 		}
-		s.advance()
+		if char == '$' && s.peek() == '{' {
+			s.advance()
+			s.interpolations = append(s.interpolations, 0)
+			if escapeErr != nil {
+				return escapeErr
+			}
+			s.addToken(token.INTERPOLATION, result.String())
+			return nil
+		}
+		if char != '\\' || s.isAtEnd() {
+			result.WriteRune(char)
+			continue
+		}
+
+		decoded, err := s.parseEscape()
+		if err != nil && escapeErr == nil {
+			escapeErr = err
+		}
+		result.WriteString(decoded)
 	}
 
 	if s.isAtEnd() {
 		return errors.NewLexicalError(s.Line, "", "Unterminated string.")
 	}
 
-	result := s.getCurrentSubString()[1:]
 	s.advance()
-	s.addToken(token.STRING, result)
+	if escapeErr != nil {
+		return escapeErr
+	}
+	s.addToken(token.STRING, result.String())
+	return nil
+}
+
+func (s *Scanner) lineComment() {
+	for !s.isAtEnd() && s.peek() != '\n' {
+		s.advance()
+	}
+
+	comment := s.getCurrentSubString()
+	if strings.HasPrefix(comment, "///") {
+		line := strings.TrimPrefix(comment, "///")
+		s.doc = append(s.doc, strings.TrimPrefix(line, " "))
+	}
+}
+
+// blockComment skips a "/* ... */" comment, which may contain nested block
+// comments.
+func (s *Scanner) blockComment() error {
+	depth := 1
+	for depth > 0 {
+		if s.isAtEnd() {
+			return errors.NewLexicalError(s.Line, "", "Unterminated block comment.")
+		}
+
+		char := s.advance()
+		if char == '\n' {
+			s.Line++
+		} else if char == '/' && s.match('*') {
+			depth++
+		} else if char == '*' && s.match('/') {
+			depth--
+		}
+	}
 	return nil
 }
 
@@ -150,25 +321,70 @@ func (s *Scanner) scanToken() error {
 	case ')':
 		s.addToken(token.RIGHT_PAREN, nil)
 	case '{':
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1]++
+		}
 		s.addToken(token.LEFT_BRACE, nil)
 	case '}':
+		depth := len(s.interpolations) - 1
+		if depth >= 0 && s.interpolations[depth] == 0 {
+			s.interpolations = s.interpolations[:depth]
+			return s.parseString()
+		}
+		if depth >= 0 {
+			s.interpolations[depth]--
+		}
 		s.addToken(token.RIGHT_BRACE, nil)
+	case '[':
+		s.addToken(token.LEFT_BRACKET, nil)
+	case ']':
+		s.addToken(token.RIGHT_BRACKET, nil)
+	case ':':
+		s.addToken(token.COLON, nil)
+	case '?':
+		s.addToken(token.QUESTION, nil)
 	case ',':
 		s.addToken(token.COMMA, nil)
 	case '.':
 		s.addToken(token.DOT, nil)
 	case '-':
-		s.addToken(token.MINUS, nil)
+		tokentype := token.MINUS
+		if s.match('=') {
+			tokentype = token.MINUS_EQUAL
+		}
+		s.addToken(tokentype, nil)
 	case '+':
-		s.addToken(token.PLUS, nil)
+		tokentype := token.PLUS
+		if s.match('=') {
+			tokentype = token.PLUS_EQUAL
+		}
+		s.addToken(tokentype, nil)
 	case ';':
 		s.addToken(token.SEMICOLON, nil)
 	case '*':
-		s.addToken(token.STAR, nil)
+		tokentype := token.STAR
+		if s.match('*') {
+			tokentype = token.STAR_STAR
+		} else if s.match('=') {
+			tokentype = token.STAR_EQUAL
+		}
+		s.addToken(tokentype, nil)
+	case '%':
+		s.addToken(token.PERCENT, nil)
+	case '&':
+		s.addToken(token.AMPERSAND, nil)
+	case '|':
+		s.addToken(token.PIPE, nil)
+	case '^':
+		s.addToken(token.CARET, nil)
+	case '~':
+		s.addToken(token.TILDE, nil)
 	case '=':
 		tokentype := token.EQUAL
 		if s.match('=') {
 			tokentype = token.EQUAL_EQUAL
+		} else if s.match('>') {
+			tokentype = token.ARROW
 		}
 		s.addToken(tokentype, nil)
 	case '!':
@@ -181,19 +397,25 @@ func (s *Scanner) scanToken() error {
 		tokentype := token.LESS
 		if s.match('=') {
 			tokentype = token.LESS_EQUAL
+		} else if s.match('<') {
+			tokentype = token.LESS_LESS
 		}
 		s.addToken(tokentype, nil)
 	case '>':
 		tokentype := token.GREATER
 		if s.match('=') {
 			tokentype = token.GREATER_EQUAL
+		} else if s.match('>') {
+			tokentype = token.GREATER_GREATER
 		}
 		s.addToken(tokentype, nil)
 	case '/':
 		if s.match('/') {
-			for !s.isAtEnd() && s.peek() != '\n' {
-				s.advance()
-			}
+			s.lineComment()
+		} else if s.match('*') {
+			return s.blockComment()
+		} else if s.match('=') {
+			s.addToken(token.SLASH_EQUAL, nil)
 		} else {
 			s.addToken(token.SLASH, nil)
 		}
@@ -209,7 +431,7 @@ func (s *Scanner) scanToken() error {
 		s.Line++
 	default:
 		if functions.IsDigit(char) {
-			s.parseNumber()
+			return s.parseNumber()
 		} else if functions.IsAlpha(char) {
 			s.parseIdentifier()
 		} else {
@@ -229,6 +451,9 @@ func (s *Scanner) ScanTokens() []error {
 			retErr = append(retErr, err)
 		}
 	}
+	if len(s.interpolations) > 0 {
+		retErr = append(retErr, errors.NewLexicalError(s.Line, "", "Unterminated string."))
+	}
 	s.Tokens = append(s.Tokens, token.NewToken(token.EOF, "", nil, s.Line))
 	return retErr
 }
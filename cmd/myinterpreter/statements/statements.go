@@ -27,6 +27,7 @@ func NewBlockStatement(statements []interfaces.Statement) BlockStatement {
 type VarStatement struct {
 	Name       token.Token
 	Expression interfaces.Expr
+	Doc        string
 }
 
 func (vs VarStatement) GetExpression() (interfaces.Expr, error) {
@@ -79,3 +80,252 @@ func NewPrintStatement(expression interfaces.Expr) PrintStatement {
 		Expression: expression,
 	}
 }
+
+type IfStatement struct {
+	Condition  interfaces.Expr
+	ThenBranch interfaces.Statement
+	ElseBranch interfaces.Statement
+}
+
+func (is IfStatement) GetExpression() (interfaces.Expr, error) {
+	return is.Condition, nil
+}
+
+func (is IfStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitIfStatement(is)
+}
+
+func NewIfStatement(condition interfaces.Expr, thenBranch interfaces.Statement, elseBranch interfaces.Statement) IfStatement {
+	return IfStatement{
+		Condition:  condition,
+		ThenBranch: thenBranch,
+		ElseBranch: elseBranch,
+	}
+}
+
+// WhileStatement also represents desugared for loops, whose increment is
+// kept apart from the body so that continue still runs it.
+type WhileStatement struct {
+	Condition interfaces.Expr
+	Body      interfaces.Statement
+	Increment interfaces.Expr
+}
+
+func (ws WhileStatement) GetExpression() (interfaces.Expr, error) {
+	return ws.Condition, nil
+}
+
+func (ws WhileStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitWhileStatement(ws)
+}
+
+func NewWhileStatement(condition interfaces.Expr, body interfaces.Statement, increment interfaces.Expr) WhileStatement {
+	return WhileStatement{
+		Condition: condition,
+		Body:      body,
+		Increment: increment,
+	}
+}
+
+type ForInStatement struct {
+	Name     token.Token
+	Keyword  token.Token
+	Iterable interfaces.Expr
+	Body     interfaces.Statement
+}
+
+func (fs ForInStatement) GetExpression() (interfaces.Expr, error) {
+	return fs.Iterable, nil
+}
+
+func (fs ForInStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitForInStatement(fs)
+}
+
+func NewForInStatement(name token.Token, keyword token.Token, iterable interfaces.Expr, body interfaces.Statement) ForInStatement {
+	return ForInStatement{
+		Name:     name,
+		Keyword:  keyword,
+		Iterable: iterable,
+		Body:     body,
+	}
+}
+
+type FunctionStatement struct {
+	Name   token.Token
+	Params []token.Token
+	Body   []interfaces.Statement
+	Doc    string
+}
+
+// GetExpression implements interfaces.Statement.
+func (fs FunctionStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (fs FunctionStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitFunctionStatement(fs)
+}
+
+func NewFunctionStatement(name token.Token, params []token.Token, body []interfaces.Statement) FunctionStatement {
+	return FunctionStatement{
+		Name:   name,
+		Params: params,
+		Body:   body,
+	}
+}
+
+type ReturnStatement struct {
+	Keyword token.Token
+	Value   interfaces.Expr
+}
+
+func (rs ReturnStatement) GetExpression() (interfaces.Expr, error) {
+	return rs.Value, nil
+}
+
+func (rs ReturnStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitReturnStatement(rs)
+}
+
+func NewReturnStatement(keyword token.Token, value interfaces.Expr) ReturnStatement {
+	return ReturnStatement{
+		Keyword: keyword,
+		Value:   value,
+	}
+}
+
+type ThrowStatement struct {
+	Keyword token.Token
+	Value   interfaces.Expr
+}
+
+func (ts ThrowStatement) GetExpression() (interfaces.Expr, error) {
+	return ts.Value, nil
+}
+
+func (ts ThrowStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitThrowStatement(ts)
+}
+
+func NewThrowStatement(keyword token.Token, value interfaces.Expr) ThrowStatement {
+	return ThrowStatement{
+		Keyword: keyword,
+		Value:   value,
+	}
+}
+
+// TryStatement has a nil CatchBody when there is no catch clause and a nil
+// FinallyBody when there is no finally clause; the parser requires one.
+type TryStatement struct {
+	Body        []interfaces.Statement
+	CatchName   token.Token
+	CatchBody   []interfaces.Statement
+	FinallyBody []interfaces.Statement
+}
+
+// GetExpression implements interfaces.Statement.
+func (ts TryStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (ts TryStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitTryStatement(ts)
+}
+
+func NewTryStatement(body []interfaces.Statement, catchName token.Token, catchBody []interfaces.Statement, finallyBody []interfaces.Statement) TryStatement {
+	return TryStatement{
+		Body:        body,
+		CatchName:   catchName,
+		CatchBody:   catchBody,
+		FinallyBody: finallyBody,
+	}
+}
+
+type BreakStatement struct {
+	Keyword token.Token
+}
+
+// GetExpression implements interfaces.Statement.
+func (bs BreakStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (bs BreakStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitBreakStatement(bs)
+}
+
+func NewBreakStatement(keyword token.Token) BreakStatement {
+	return BreakStatement{
+		Keyword: keyword,
+	}
+}
+
+type ContinueStatement struct {
+	Keyword token.Token
+}
+
+// GetExpression implements interfaces.Statement.
+func (cs ContinueStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (cs ContinueStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitContinueStatement(cs)
+}
+
+func NewContinueStatement(keyword token.Token) ContinueStatement {
+	return ContinueStatement{
+		Keyword: keyword,
+	}
+}
+
+// ImportStatement binds the module loaded from Path, a string token, to
+// Name in the current scope.
+type ImportStatement struct {
+	Keyword token.Token
+	Path    token.Token
+	Name    token.Token
+}
+
+// GetExpression implements interfaces.Statement.
+func (is ImportStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (is ImportStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitImportStatement(is)
+}
+
+func NewImportStatement(keyword token.Token, path token.Token, name token.Token) ImportStatement {
+	return ImportStatement{
+		Keyword: keyword,
+		Path:    path,
+		Name:    name,
+	}
+}
+
+// ExportStatement wraps a top-level var or fun declaration whose name the
+// module exposes to importers.
+type ExportStatement struct {
+	Keyword     token.Token
+	Name        token.Token
+	Declaration interfaces.Statement
+}
+
+// GetExpression implements interfaces.Statement.
+func (es ExportStatement) GetExpression() (interfaces.Expr, error) {
+	panic("unimplemented")
+}
+
+func (es ExportStatement) Accept(visitor interfaces.StatementVisitor) (interface{}, error) {
+	return visitor.VisitExportStatement(es)
+}
+
+func NewExportStatement(keyword token.Token, name token.Token, declaration interfaces.Statement) ExportStatement {
+	return ExportStatement{
+		Keyword:     keyword,
+		Name:        name,
+		Declaration: declaration,
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expr"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/interfaces"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/statements"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// findTestFiles returns the *_test.lox files under dir in lexical order.
+func findTestFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_test.lox") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// findTests returns the names of the parameterless top-level functions
+// whose names start with "test", in declaration order.
+func findTests(program []interfaces.Statement) []token.Token {
+	var tests []token.Token
+	for _, statement := range program {
+		if export, ok := statement.(statements.ExportStatement); ok {
+			statement = export.Declaration
+		}
+		function, ok := statement.(statements.FunctionStatement)
+		if ok && strings.HasPrefix(function.Name.Lexeme, "test") && len(function.Params) == 0 {
+			tests = append(tests, function.Name)
+		}
+	}
+	return tests
+}
+
+// failure renders why a test failed, with the traceback of runtime errors.
+func failure(err error) string {
+	if runtimeError, ok := err.(errors.RuntimeError); ok {
+		return runtimeError.Traceback()
+	}
+	return err.Error()
+}
+
+// runTest runs a test file's top-level code in a fresh interpreter, then
+// calls the test function name.
+func runTest(ctx context.Context, filename string, program []interfaces.Statement, name token.Token, opts options) error {
+	call := expr.NewCallExpr(expr.NewVarExpr(name), name, nil)
+	program = append(program[:len(program):len(program)], statements.NewExpressionStatement(call))
+
+	interpreter := newInterpreter(filename, opts)
+	err := interpreter.InterpretContext(ctx, program)
+	if exitError, ok := err.(errors.ExitError); ok && exitError.Code == 0 {
+		return nil
+	}
+	return err
+}
+
+// runTests runs every test in the *_test.lox files under dir, printing a
+// line per test and a summary, and returns the exit status: 0 when every
+// test passed, 1 otherwise.
+func runTests(dir string, opts options) int {
+	files, err := findTestFiles(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding tests: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	passed, failed := 0, 0
+	for _, filename := range files {
+		fileContents, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Printf("FAIL %s\n    %v\n", filename, err)
+			failed++
+			continue
+		}
+		s, errs := scantokens(fileContents)
+		if len(errs) > 0 {
+			fmt.Printf("FAIL %s\n    %v\n", filename, errs[0])
+			failed++
+			continue
+		}
+		program, err := parseStatement(s.Tokens)
+		if err != nil {
+			fmt.Printf("FAIL %s\n    %v\n", filename, err)
+			failed++
+			continue
+		}
+
+		for _, test := range findTests(program) {
+			err := runTest(ctx, filename, program, test, opts)
+			if err == nil {
+				fmt.Printf("PASS %s %s\n", filename, test.Lexeme)
+				passed++
+				continue
+			}
+			fmt.Printf("FAIL %s %s\n", filename, test.Lexeme)
+			fmt.Println("    " + strings.ReplaceAll(failure(err), "\n", "\n    "))
+			failed++
+			if _, ok := err.(errors.CancellationError); ok {
+				fmt.Printf("\n%d passed, %d failed\n", passed, failed)
+				return 1
+			}
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package token
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/functions"
 )
@@ -10,50 +11,81 @@ type TokenType string
 
 const (
 	// Single-character tokens.
-	LEFT_PAREN  TokenType = "LEFT_PAREN"
-	RIGHT_PAREN TokenType = "RIGHT_PAREN"
-	LEFT_BRACE  TokenType = "LEFT_BRACE"
-	RIGHT_BRACE TokenType = "RIGHT_BRACE"
-	COMMA       TokenType = "COMMA"
-	DOT         TokenType = "DOT"
-	MINUS       TokenType = "MINUS"
-	PLUS        TokenType = "PLUS"
-	SEMICOLON   TokenType = "SEMICOLON"
-	SLASH       TokenType = "SLASH"
-	STAR        TokenType = "STAR"
+	LEFT_PAREN    TokenType = "LEFT_PAREN"
+	RIGHT_PAREN   TokenType = "RIGHT_PAREN"
+	LEFT_BRACE    TokenType = "LEFT_BRACE"
+	RIGHT_BRACE   TokenType = "RIGHT_BRACE"
+	LEFT_BRACKET  TokenType = "LEFT_BRACKET"
+	RIGHT_BRACKET TokenType = "RIGHT_BRACKET"
+	COLON         TokenType = "COLON"
+	QUESTION      TokenType = "QUESTION"
+	COMMA         TokenType = "COMMA"
+	DOT           TokenType = "DOT"
+	MINUS         TokenType = "MINUS"
+	PLUS          TokenType = "PLUS"
+	PERCENT       TokenType = "PERCENT"
+	SEMICOLON     TokenType = "SEMICOLON"
+	SLASH         TokenType = "SLASH"
+	STAR          TokenType = "STAR"
+	AMPERSAND     TokenType = "AMPERSAND"
+	PIPE          TokenType = "PIPE"
+	CARET         TokenType = "CARET"
+	TILDE         TokenType = "TILDE"
 
 	// One or two character tokens.
-	BANG          TokenType = "BANG"
-	BANG_EQUAL    TokenType = "BANG_EQUAL"
-	EQUAL         TokenType = "EQUAL"
-	EQUAL_EQUAL   TokenType = "EQUAL_EQUAL"
-	GREATER       TokenType = "GREATER"
-	GREATER_EQUAL TokenType = "GREATER_EQUAL"
-	LESS          TokenType = "LESS"
-	LESS_EQUAL    TokenType = "LESS_EQUAL"
+	BANG            TokenType = "BANG"
+	BANG_EQUAL      TokenType = "BANG_EQUAL"
+	EQUAL           TokenType = "EQUAL"
+	EQUAL_EQUAL     TokenType = "EQUAL_EQUAL"
+	GREATER         TokenType = "GREATER"
+	GREATER_EQUAL   TokenType = "GREATER_EQUAL"
+	LESS            TokenType = "LESS"
+	LESS_EQUAL      TokenType = "LESS_EQUAL"
+	LESS_LESS       TokenType = "LESS_LESS"
+	GREATER_GREATER TokenType = "GREATER_GREATER"
+	STAR_STAR       TokenType = "STAR_STAR"
+	PLUS_EQUAL      TokenType = "PLUS_EQUAL"
+	MINUS_EQUAL     TokenType = "MINUS_EQUAL"
+	STAR_EQUAL      TokenType = "STAR_EQUAL"
+	SLASH_EQUAL     TokenType = "SLASH_EQUAL"
+	ARROW           TokenType = "ARROW"
 
 	// Literals.
 	IDENTIFIER TokenType = "IDENTIFIER"
 	STRING     TokenType = "STRING"
 	NUMBER     TokenType = "NUMBER"
+	// INTERPOLATION is a string segment ending in "${". The tokens of the
+	// embedded expression follow it, and the string resumes after the
+	// matching "}" as another INTERPOLATION or a final STRING.
+	INTERPOLATION TokenType = "INTERPOLATION"
 
 	// Keywords.
-	AND    TokenType = "AND"
-	CLASS  TokenType = "CLASS"
-	ELSE   TokenType = "ELSE"
-	FALSE  TokenType = "FALSE"
-	FUN    TokenType = "FUN"
-	FOR    TokenType = "FOR"
-	IF     TokenType = "IF"
-	NIL    TokenType = "NIL"
-	OR     TokenType = "OR"
-	PRINT  TokenType = "PRINT"
-	RETURN TokenType = "RETURN"
-	SUPER  TokenType = "SUPER"
-	THIS   TokenType = "THIS"
-	TRUE   TokenType = "TRUE"
-	VAR    TokenType = "VAR"
-	WHILE  TokenType = "WHILE"
+	AND      TokenType = "AND"
+	AS       TokenType = "AS"
+	BREAK    TokenType = "BREAK"
+	CATCH    TokenType = "CATCH"
+	CLASS    TokenType = "CLASS"
+	CONTINUE TokenType = "CONTINUE"
+	ELSE     TokenType = "ELSE"
+	EXPORT   TokenType = "EXPORT"
+	FALSE    TokenType = "FALSE"
+	FINALLY  TokenType = "FINALLY"
+	FUN      TokenType = "FUN"
+	FOR      TokenType = "FOR"
+	IF       TokenType = "IF"
+	IMPORT   TokenType = "IMPORT"
+	IN       TokenType = "IN"
+	NIL      TokenType = "NIL"
+	OR       TokenType = "OR"
+	PRINT    TokenType = "PRINT"
+	RETURN   TokenType = "RETURN"
+	SUPER    TokenType = "SUPER"
+	THIS     TokenType = "THIS"
+	THROW    TokenType = "THROW"
+	TRUE     TokenType = "TRUE"
+	TRY      TokenType = "TRY"
+	VAR      TokenType = "VAR"
+	WHILE    TokenType = "WHILE"
 
 	EOF TokenType = "EOF"
 )
@@ -63,6 +95,9 @@ type Token struct {
 	Lexeme    string
 	Literal   interface{}
 	Line      int
+	// Doc is the text of the "///" comment lines directly preceding the
+	// token, kept as trivia for documentation tooling.
+	Doc string
 }
 
 func (t *Token) String() string {
@@ -70,8 +105,10 @@ func (t *Token) String() string {
 
 	if t.Literal == nil {
 		stringLiteral = "null"
-	} else if t.TokenType == NUMBER {
-		stringLiteral = functions.FormatWithFixedPrecision(t.Literal.(float64))
+	} else if integer, ok := t.Literal.(int64); ok && t.TokenType == NUMBER {
+		stringLiteral = strconv.FormatInt(integer, 10)
+	} else if number, ok := t.Literal.(float64); ok && t.TokenType == NUMBER {
+		stringLiteral = functions.FormatWithFixedPrecision(number)
 	} else {
 		stringLiteral = fmt.Sprintf("%v", t.Literal)
 	}
@@ -0,0 +1,134 @@
+package visitor
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/statements"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Callable is implemented by every value that can appear on the left of a
+// call expression. An Arity of -1 accepts any number of arguments.
+type Callable interface {
+	Arity() int
+	Call(interpreter *Interpreter, arguments []interface{}) (interface{}, error)
+}
+
+func callableName(callable Callable) string {
+	switch callable := callable.(type) {
+	case *Function:
+		if callable.isAnonymous() {
+			return "<anonymous>"
+		}
+		return callable.Declaration.Name.Lexeme
+	case *NativeFunction:
+		return callable.Name
+	}
+	return "<anonymous>"
+}
+
+// checkArgumentRange reports an error unless a variadic native got between
+// min and max arguments.
+func checkArgumentRange(arguments []interface{}, min int, max int) error {
+	if len(arguments) < min || len(arguments) > max {
+		return errors.NewRuntimeError(token.NewTokenNil(), fmt.Sprintf("Expected %d or %d arguments but got %d.", min, max, len(arguments)))
+	}
+	return nil
+}
+
+// returnValue unwinds the Go call stack from a return statement back to the
+// function call that is executing it.
+type returnValue struct {
+	Value interface{}
+}
+
+func (rv returnValue) Error() string {
+	return "return"
+}
+
+// breakSignal and continueSignal unwind from a break or continue statement
+// to the innermost enclosing loop, restoring environments on the way.
+type breakSignal struct{}
+
+func (bs breakSignal) Error() string {
+	return "break"
+}
+
+type continueSignal struct{}
+
+func (cs continueSignal) Error() string {
+	return "continue"
+}
+
+type Function struct {
+	Declaration statements.FunctionStatement
+	Closure     environment.Environment
+	// File names the module the function was declared in, empty for the
+	// script being run.
+	File string
+}
+
+// isAnonymous reports whether f came from a function expression, whose
+// declaration is named by its fun or => token rather than an identifier.
+func (f *Function) isAnonymous() bool {
+	return f.Declaration.Name.TokenType != token.IDENTIFIER
+}
+
+func (f *Function) Arity() int {
+	return len(f.Declaration.Params)
+}
+
+func (f *Function) Call(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+	env := environment.NewEnvironment(&f.Closure)
+	for i, param := range f.Declaration.Params {
+		env.Define(param.Lexeme, arguments[i])
+	}
+
+	err := interpreter.executeBlock(f.Declaration.Body, env)
+	if ret, ok := err.(returnValue); ok {
+		return ret.Value, nil
+	}
+	return nil, err
+}
+
+func (f *Function) String() string {
+	if f.isAnonymous() {
+		return "<fn>"
+	}
+	return "<fn " + f.Declaration.Name.Lexeme + ">"
+}
+
+func NewFunction(declaration statements.FunctionStatement, closure environment.Environment) *Function {
+	return &Function{
+		Declaration: declaration,
+		Closure:     closure,
+	}
+}
+
+type NativeFunction struct {
+	Name     string
+	arity    int
+	function func(interpreter *Interpreter, arguments []interface{}) (interface{}, error)
+}
+
+func (nf *NativeFunction) Arity() int {
+	return nf.arity
+}
+
+func (nf *NativeFunction) Call(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+	return nf.function(interpreter, arguments)
+}
+
+func (nf *NativeFunction) String() string {
+	return "<native fn>"
+}
+
+func NewNativeFunction(name string, arity int, function func(interpreter *Interpreter, arguments []interface{}) (interface{}, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:     name,
+		arity:    arity,
+		function: function,
+	}
+}
@@ -0,0 +1,217 @@
+package visitor
+
+import (
+	"bufio"
+	goerrors "errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// files holds what the io module may touch. It is shared by an interpreter
+// and the modules it imports.
+type files struct {
+	// allowed lists the absolute directories, symlinks resolved, whose
+	// contents scripts may read and write. It is empty by default, so a
+	// sandboxed interpreter has no filesystem access.
+	allowed []string
+	stdin   *bufio.Reader
+}
+
+func newFiles() *files {
+	return &files{
+		stdin: bufio.NewReader(os.Stdin),
+	}
+}
+
+// SetAllowedDirs replaces the directories the io module may access. Files
+// in their subdirectories are allowed too.
+func (interpreter *Interpreter) SetAllowedDirs(dirs []string) error {
+	allowed := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		absolute, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		resolved, err := filepath.EvalSymlinks(absolute)
+		if err != nil {
+			return err
+		}
+		allowed = append(allowed, resolved)
+	}
+	interpreter.files.allowed = allowed
+	return nil
+}
+
+// SetStdin replaces the reader io.readLine and io.readAll consume.
+func (interpreter *Interpreter) SetStdin(reader io.Reader) {
+	interpreter.files.stdin = bufio.NewReader(reader)
+}
+
+// resolve returns the absolute form of path with symlinks resolved, so a
+// link inside an allowed directory can't reach outside it, or an error if
+// it isn't inside an allowed directory. The last element may not exist yet.
+func (f *files) resolve(path string) (string, error) {
+	return resolveWithin(path, f.allowed)
+}
+
+// resolveWithin is resolve with dirs in place of the allowed directories.
+func resolveWithin(path string, dirs []string) (string, error) {
+	denied := errors.NewRuntimeError(token.NewTokenNil(), "Access to '"+path+"' is not allowed.")
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", denied
+	}
+	resolved, err := filepath.EvalSymlinks(absolute)
+	if err != nil {
+		// Only a missing file may be resolved through its parent. A
+		// dangling symlink would be followed when the file is created.
+		_, statErr := os.Lstat(absolute)
+		if !goerrors.Is(statErr, fs.ErrNotExist) {
+			return "", denied
+		}
+		parent, parentErr := filepath.EvalSymlinks(filepath.Dir(absolute))
+		if parentErr != nil {
+			return "", denied
+		}
+		resolved = filepath.Join(parent, filepath.Base(absolute))
+	}
+
+	for _, dir := range dirs {
+		relative, err := filepath.Rel(dir, resolved)
+		if err != nil {
+			continue
+		}
+		if relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", denied
+}
+
+// ioError reports a failed operation on path with the reason from the
+// operating system, without repeating the path it already names.
+func ioError(action string, path string, err error) error {
+	var pathError *os.PathError
+	if goerrors.As(err, &pathError) {
+		err = pathError.Err
+	}
+	return errors.NewRuntimeError(token.NewTokenNil(), "Could not "+action+" '"+path+"': "+err.Error()+".")
+}
+
+// pathArgument resolves the path a native received as its first argument.
+func (f *files) pathArgument(function string, arguments []interface{}) (string, string, error) {
+	path, ok := arguments[0].(string)
+	if !ok {
+		return "", "", errors.NewRuntimeError(token.NewTokenNil(), "Argument to "+function+"() must be a string.")
+	}
+	resolved, err := f.resolve(path)
+	return path, resolved, err
+}
+
+// writer returns a native that writes its string argument to a file, opened
+// with flag.
+func writer(name string, action string, flag int) *NativeFunction {
+	return NewNativeFunction(name, 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		path, resolved, err := interpreter.files.pathArgument(name, arguments)
+		if err != nil {
+			return nil, err
+		}
+		content, ok := arguments[1].(string)
+		if !ok {
+			return nil, errors.NewRuntimeError(token.NewTokenNil(), "Content to "+name+"() must be a string.")
+		}
+
+		file, err := os.OpenFile(resolved, flag, 0644)
+		if err != nil {
+			return nil, ioError(action, path, err)
+		}
+		_, err = file.WriteString(content)
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, ioError(action, path, err)
+		}
+		return nil, nil
+	})
+}
+
+func newIOModule() *Module {
+	return newNativeModule("io", map[string]interface{}{
+		// readLine returns the next line of stdin without its line ending,
+		// or nil once stdin is exhausted.
+		"readLine": NewNativeFunction("readLine", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			line, err := interpreter.files.stdin.ReadString('\n')
+			if err == io.EOF && line == "" {
+				return nil, nil
+			}
+			if err != nil && err != io.EOF {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Could not read stdin: "+err.Error()+".")
+			}
+			line = strings.TrimSuffix(line, "\n")
+			return strings.TrimSuffix(line, "\r"), nil
+		}),
+
+		"readAll": NewNativeFunction("readAll", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			content, err := io.ReadAll(interpreter.files.stdin)
+			if err != nil {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Could not read stdin: "+err.Error()+".")
+			}
+			return string(content), nil
+		}),
+
+		"readFile": NewNativeFunction("readFile", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			path, resolved, err := interpreter.files.pathArgument("readFile", arguments)
+			if err != nil {
+				return nil, err
+			}
+			content, err := os.ReadFile(resolved)
+			if err != nil {
+				return nil, ioError("read", path, err)
+			}
+			return string(content), nil
+		}),
+
+		"writeFile":  writer("writeFile", "write", os.O_WRONLY|os.O_CREATE|os.O_TRUNC),
+		"appendFile": writer("appendFile", "append to", os.O_WRONLY|os.O_CREATE|os.O_APPEND),
+
+		// listDir returns the sorted names of the entries in a directory.
+		"listDir": NewNativeFunction("listDir", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			path, resolved, err := interpreter.files.pathArgument("listDir", arguments)
+			if err != nil {
+				return nil, err
+			}
+			entries, err := os.ReadDir(resolved)
+			if err != nil {
+				return nil, ioError("list", path, err)
+			}
+			names := make([]string, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+			sort.Strings(names)
+			elements := make([]interface{}, len(names))
+			for i, name := range names {
+				elements[i] = name
+			}
+			return NewList(elements), nil
+		}),
+
+		"exists": NewNativeFunction("exists", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			_, resolved, err := interpreter.files.pathArgument("exists", arguments)
+			if err != nil {
+				return nil, err
+			}
+			_, err = os.Stat(resolved)
+			return err == nil, nil
+		}),
+	})
+}
@@ -0,0 +1,78 @@
+package visitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveWithin(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := filepath.Join(root, "allowed")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{allowed, filepath.Join(allowed, "sub"), outside, filepath.Join(root, "allowed-other")} {
+		err := os.Mkdir(dir, 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{filepath.Join(allowed, "file.txt"), filepath.Join(outside, "secret.txt")} {
+		err := os.WriteFile(file, nil, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"link-in":  "file.txt",
+		"link-out": filepath.Join(outside, "secret.txt"),
+		"dangling": filepath.Join(outside, "new.txt"),
+		"dir-out":  outside,
+	}
+	for name, target := range links {
+		err := os.Symlink(target, filepath.Join(allowed, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		// want is the resolved path, relative to root, or empty when the
+		// path must be denied.
+		want string
+	}{
+		{"allowed", "allowed"},
+		{"allowed/file.txt", "allowed/file.txt"},
+		{"allowed/new.txt", "allowed/new.txt"},
+		{"allowed/sub/new.txt", "allowed/sub/new.txt"},
+		{"allowed/sub/../file.txt", "allowed/file.txt"},
+		{"allowed/link-in", "allowed/file.txt"},
+		{"allowed/missing/new.txt", ""},
+		{"allowed/../outside/secret.txt", ""},
+		{"allowed/sub/../../outside/new.txt", ""},
+		{"allowed/link-out", ""},
+		{"allowed/dangling", ""},
+		{"allowed/dir-out/secret.txt", ""},
+		{"allowed/dir-out/new.txt", ""},
+		{"allowed-other/new.txt", ""},
+		{".", ""},
+	}
+	for _, test := range tests {
+		// Joined by hand so resolveWithin sees the ".." elements.
+		path := root + string(filepath.Separator) + filepath.FromSlash(test.path)
+		got, err := resolveWithin(path, []string{allowed})
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("resolveWithin(%q) = %q, want it denied", test.path, got)
+			}
+			continue
+		}
+		want := filepath.Join(root, filepath.FromSlash(test.want))
+		if err != nil || got != want {
+			t.Errorf("resolveWithin(%q) = %q, %v, want %q", test.path, got, err, want)
+		}
+	}
+}
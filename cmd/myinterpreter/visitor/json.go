@@ -0,0 +1,504 @@
+package visitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// jsonDecoder turns JSON text into Lox values: objects become maps with
+// keys in document order, arrays lists, and null nil. Integral numbers
+// become integers when integer literals are enabled.
+type jsonDecoder struct {
+	source   string
+	current  int
+	line     int
+	column   int
+	integers bool
+	// depth is the number of arrays and objects open at the current
+	// position.
+	depth int
+}
+
+// maxJSONDepth bounds the nesting of arrays and objects, which are decoded
+// and encoded recursively.
+const maxJSONDepth = 1000
+
+// nest enters an array or object, failing when nesting gets too deep.
+func (d *jsonDecoder) nest() error {
+	d.depth++
+	if d.depth > maxJSONDepth {
+		return d.error(fmt.Sprintf("nesting is deeper than %d levels", maxJSONDepth))
+	}
+	return nil
+}
+
+// error reports message at the current position, counting lines and
+// columns from 1 and columns in runes.
+func (d *jsonDecoder) error(message string) error {
+	return errors.NewRuntimeError(token.NewTokenNil(), fmt.Sprintf("Invalid JSON at line %d, column %d: %s.", d.line, d.column, message))
+}
+
+func (d *jsonDecoder) isAtEnd() bool {
+	return d.current >= len(d.source)
+}
+
+func (d *jsonDecoder) peek() byte {
+	if d.isAtEnd() {
+		return 0
+	}
+	return d.source[d.current]
+}
+
+func (d *jsonDecoder) advance() rune {
+	r, size := utf8.DecodeRuneInString(d.source[d.current:])
+	d.current += size
+	if r == '\n' {
+		d.line++
+		d.column = 1
+	} else {
+		d.column++
+	}
+	return r
+}
+
+func (d *jsonDecoder) skipWhitespace() {
+	for !d.isAtEnd() {
+		switch d.peek() {
+		case ' ', '\t', '\r', '\n':
+			d.advance()
+		default:
+			return
+		}
+	}
+}
+
+// describe names the next character for error messages.
+func (d *jsonDecoder) describe() string {
+	if d.isAtEnd() {
+		return "end of input"
+	}
+	r, _ := utf8.DecodeRuneInString(d.source[d.current:])
+	return strconv.QuoteRune(r)
+}
+
+func (d *jsonDecoder) expect(literal string, value interface{}) (interface{}, error) {
+	if !strings.HasPrefix(d.source[d.current:], literal) {
+		return nil, d.error("unexpected " + d.describe())
+	}
+	for range literal {
+		d.advance()
+	}
+	return value, nil
+}
+
+func (d *jsonDecoder) value() (interface{}, error) {
+	d.skipWhitespace()
+	switch c := d.peek(); {
+	case c == '{':
+		return d.object()
+	case c == '[':
+		return d.array()
+	case c == '"':
+		return d.string()
+	case c == 't':
+		return d.expect("true", true)
+	case c == 'f':
+		return d.expect("false", false)
+	case c == 'n':
+		return d.expect("null", nil)
+	case c == '-' || (c >= '0' && c <= '9'):
+		return d.number()
+	}
+	return nil, d.error("expected a value but found " + d.describe())
+}
+
+func (d *jsonDecoder) object() (interface{}, error) {
+	err := d.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	d.advance()
+	result := NewMap()
+	d.skipWhitespace()
+	if d.peek() == '}' {
+		d.advance()
+		return result, nil
+	}
+	for {
+		d.skipWhitespace()
+		if d.peek() != '"' {
+			return nil, d.error("expected a string key but found " + d.describe())
+		}
+		key, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		d.skipWhitespace()
+		if d.peek() != ':' {
+			return nil, d.error("expected ':' after object key but found " + d.describe())
+		}
+		d.advance()
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		result.Set(key, value)
+
+		d.skipWhitespace()
+		switch d.peek() {
+		case ',':
+			d.advance()
+		case '}':
+			d.advance()
+			return result, nil
+		default:
+			return nil, d.error("expected ',' or '}' in object but found " + d.describe())
+		}
+	}
+}
+
+func (d *jsonDecoder) array() (interface{}, error) {
+	err := d.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	d.advance()
+	elements := []interface{}{}
+	d.skipWhitespace()
+	if d.peek() == ']' {
+		d.advance()
+		return NewList(elements), nil
+	}
+	for {
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+
+		d.skipWhitespace()
+		switch d.peek() {
+		case ',':
+			d.advance()
+		case ']':
+			d.advance()
+			return NewList(elements), nil
+		default:
+			return nil, d.error("expected ',' or ']' in array but found " + d.describe())
+		}
+	}
+}
+
+func (d *jsonDecoder) hex4() (rune, error) {
+	if d.current+4 > len(d.source) {
+		return 0, d.error("incomplete unicode escape")
+	}
+	value, err := strconv.ParseUint(d.source[d.current:d.current+4], 16, 32)
+	if err != nil {
+		return 0, d.error("invalid unicode escape")
+	}
+	for i := 0; i < 4; i++ {
+		d.advance()
+	}
+	return rune(value), nil
+}
+
+func (d *jsonDecoder) string() (string, error) {
+	d.advance()
+	var sb strings.Builder
+	for {
+		if d.isAtEnd() {
+			return "", d.error("unterminated string")
+		}
+		c := d.peek()
+		if c == '"' {
+			d.advance()
+			return sb.String(), nil
+		}
+		if c < 0x20 {
+			return "", d.error("control character in string")
+		}
+		if c != '\\' {
+			sb.WriteRune(d.advance())
+			continue
+		}
+
+		d.advance()
+		escape := d.peek()
+		if d.isAtEnd() {
+			return "", d.error("unterminated string")
+		}
+		switch escape {
+		case '"', '\\', '/':
+			sb.WriteByte(escape)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			d.advance()
+			r, err := d.hex4()
+			if err != nil {
+				return "", err
+			}
+			// A high surrogate combines with an escaped low surrogate
+			// into a single character outside the basic plane. Any other
+			// escape after it is left to be decoded on its own.
+			if r >= 0xd800 && r < 0xdc00 && strings.HasPrefix(d.source[d.current:], "\\u") {
+				saved := *d
+				d.advance()
+				d.advance()
+				low, err := d.hex4()
+				if err == nil && utf16.DecodeRune(r, low) != utf8.RuneError {
+					r = utf16.DecodeRune(r, low)
+				} else {
+					*d = saved
+				}
+			}
+			// Unpaired surrogates are written as U+FFFD.
+			sb.WriteRune(r)
+			continue
+		default:
+			return "", d.error("invalid escape " + d.describe())
+		}
+		d.advance()
+	}
+}
+
+func (d *jsonDecoder) digits() int {
+	count := 0
+	for c := d.peek(); c >= '0' && c <= '9'; c = d.peek() {
+		d.advance()
+		count++
+	}
+	return count
+}
+
+func (d *jsonDecoder) number() (interface{}, error) {
+	start := d.current
+	if d.peek() == '-' {
+		d.advance()
+	}
+	if d.peek() == '0' {
+		d.advance()
+	} else if d.digits() == 0 {
+		return nil, d.error("expected a digit but found " + d.describe())
+	}
+
+	integral := true
+	if d.peek() == '.' {
+		integral = false
+		d.advance()
+		if d.digits() == 0 {
+			return nil, d.error("expected a digit after '.' but found " + d.describe())
+		}
+	}
+	if d.peek() == 'e' || d.peek() == 'E' {
+		integral = false
+		d.advance()
+		if d.peek() == '+' || d.peek() == '-' {
+			d.advance()
+		}
+		if d.digits() == 0 {
+			return nil, d.error("expected a digit in exponent but found " + d.describe())
+		}
+	}
+
+	text := d.source[start:d.current]
+	if integral && d.integers {
+		integer, err := strconv.ParseInt(text, 10, 64)
+		if err == nil {
+			return integer, nil
+		}
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, d.error("number " + text + " is out of range")
+	}
+	return number, nil
+}
+
+func parseJSON(source string, integers bool) (interface{}, error) {
+	d := &jsonDecoder{
+		source:   source,
+		line:     1,
+		column:   1,
+		integers: integers,
+	}
+	value, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	d.skipWhitespace()
+	if !d.isAtEnd() {
+		return nil, d.error("unexpected " + d.describe() + " after value")
+	}
+	return value, nil
+}
+
+// maxIndent is the most spaces stringify indents by, as in JavaScript.
+const maxIndent = 10
+
+// jsonEncoder writes Lox values as JSON, indenting nested values by indent
+// when it isn't empty.
+type jsonEncoder struct {
+	interpreter *Interpreter
+	indent      string
+	buffer      bytes.Buffer
+	// visiting holds the lists and maps being written, to reject cycles.
+	visiting map[interface{}]bool
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.indent != "" {
+		e.buffer.WriteByte('\n')
+		e.buffer.WriteString(strings.Repeat(e.indent, depth))
+	}
+}
+
+func (e *jsonEncoder) writeString(str string) {
+	encoder := json.NewEncoder(&e.buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(str)
+	// Encode terminates every value with a newline.
+	e.buffer.Truncate(e.buffer.Len() - 1)
+}
+
+func (e *jsonEncoder) encode(value interface{}, depth int) error {
+	if depth > maxJSONDepth {
+		return errors.NewRuntimeError(token.NewTokenNil(), fmt.Sprintf("Can't convert a structure nested deeper than %d levels to JSON.", maxJSONDepth))
+	}
+	switch value := value.(type) {
+	case nil:
+		e.buffer.WriteString("null")
+	case bool:
+		e.buffer.WriteString(strconv.FormatBool(value))
+	case string:
+		e.writeString(value)
+	case int64:
+		e.buffer.WriteString(strconv.FormatInt(value, 10))
+	case *big.Int:
+		e.buffer.WriteString(value.String())
+	case decimal.Decimal:
+		e.buffer.WriteString(value.String())
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return errors.NewRuntimeError(token.NewTokenNil(), "Can't convert "+e.interpreter.Stringify(value)+" to JSON.")
+		}
+		e.buffer.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	case *List:
+		if e.visiting[value] {
+			return errors.NewRuntimeError(token.NewTokenNil(), "Can't convert a cyclic structure to JSON.")
+		}
+		e.visiting[value] = true
+		defer delete(e.visiting, value)
+
+		e.buffer.WriteByte('[')
+		for i, element := range value.Elements {
+			if i > 0 {
+				e.buffer.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			err := e.encode(element, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		if len(value.Elements) > 0 {
+			e.newline(depth)
+		}
+		e.buffer.WriteByte(']')
+	case *Map:
+		if e.visiting[value] {
+			return errors.NewRuntimeError(token.NewTokenNil(), "Can't convert a cyclic structure to JSON.")
+		}
+		e.visiting[value] = true
+		defer delete(e.visiting, value)
+
+		e.buffer.WriteByte('{')
+		for i, key := range value.Keys() {
+			if i > 0 {
+				e.buffer.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			// JSON keys are always strings, so number and boolean keys
+			// are written as their printed form.
+			e.writeString(e.interpreter.Stringify(key))
+			e.buffer.WriteByte(':')
+			if e.indent != "" {
+				e.buffer.WriteByte(' ')
+			}
+			element, _ := value.Lookup(key)
+			err := e.encode(element, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		if value.Len() > 0 {
+			e.newline(depth)
+		}
+		e.buffer.WriteByte('}')
+	default:
+		return errors.NewRuntimeError(token.NewTokenNil(), "Can't convert "+typeName(value)+" to JSON.")
+	}
+	return nil
+}
+
+func newJSONModule() *Module {
+	return newNativeModule("json", map[string]interface{}{
+		"parse": NewNativeFunction("parse", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			source, ok := arguments[0].(string)
+			if !ok {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Argument to parse() must be a string.")
+			}
+			return parseJSON(source, interpreter.modules.integers)
+		}),
+
+		// stringify takes an optional indent: a number of spaces or the
+		// string to indent with. Without one the output is compact.
+		"stringify": NewNativeFunction("stringify", -1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkArgumentRange(arguments, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			encoder := &jsonEncoder{
+				interpreter: interpreter,
+				visiting:    map[interface{}]bool{},
+			}
+			if len(arguments) == 2 && arguments[1] != nil {
+				if indent, ok := arguments[1].(string); ok {
+					encoder.indent = indent
+				} else if spaces, ok := integerArgument(arguments[1]); ok && spaces >= 0 && spaces <= maxIndent {
+					encoder.indent = strings.Repeat(" ", int(spaces))
+				} else {
+					return nil, errors.NewRuntimeError(token.NewTokenNil(), fmt.Sprintf("Indent must be a string or an integer from 0 to %d.", maxIndent))
+				}
+			}
+
+			err = encoder.encode(arguments[0], 0)
+			if err != nil {
+				return nil, err
+			}
+			return encoder.buffer.String(), nil
+		}),
+	})
+}
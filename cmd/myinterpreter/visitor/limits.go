@@ -0,0 +1,122 @@
+package visitor
+
+import (
+	"context"
+	"fmt"
+	"runtime/metrics"
+	"time"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Limits bounds the resources a single Interpret call may consume so that
+// untrusted scripts cannot hang or exhaust the host process. A zero value in
+// any field disables that limit.
+type Limits struct {
+	// MaxSteps is the number of statements that may be executed.
+	MaxSteps int
+	// Timeout is the wall-clock time the script may run for.
+	Timeout time.Duration
+	// MaxCallDepth is the deepest the function call stack may grow. Calls
+	// never nest deeper than maxStackDepth, whatever the limit.
+	MaxCallDepth int
+	// MaxHeapBytes is the approximate heap growth allowed, sampled from the
+	// Go runtime, so it also counts allocations made by the host.
+	MaxHeapBytes uint64
+}
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// maxStackDepth is the deepest calls may nest even without a call depth
+// limit. Each call takes several kilobytes of Go stack, so this stays far
+// below the point where the runtime kills the process.
+const maxStackDepth = 5000
+
+type budget struct {
+	limits    Limits
+	parent    context.Context
+	ctx       context.Context
+	steps     int
+	callDepth int
+	heapBase  uint64
+}
+
+func readHeap() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// start resets the counters for a run under ctx and returns a cancel
+// function releasing the timeout context.
+func (b *budget) start(ctx context.Context) context.CancelFunc {
+	b.steps = 0
+	b.callDepth = 0
+	if b.limits.MaxHeapBytes > 0 {
+		b.heapBase = readHeap()
+	}
+
+	b.parent = ctx
+	if b.limits.Timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(ctx, b.limits.Timeout)
+		b.ctx = timeoutCtx
+		return cancel
+	}
+	b.ctx = ctx
+	return func() {}
+}
+
+// checkpoint reports whether the run has been cancelled by the caller or has
+// run out of time. It is called at loop back-edges and function calls, which
+// every non-terminating script must pass through.
+func (b *budget) checkpoint() error {
+	if b.ctx == nil || b.ctx.Err() == nil {
+		return nil
+	}
+
+	if b.parent.Err() != nil {
+		return errors.NewCancellationError(context.Cause(b.parent))
+	}
+	return errors.NewLimitError("timeout", fmt.Sprintf("Execution exceeded the time limit of %s.", b.limits.Timeout))
+}
+
+func (b *budget) step() error {
+	b.steps++
+	if b.limits.MaxSteps > 0 && b.steps > b.limits.MaxSteps {
+		return errors.NewLimitError("steps", fmt.Sprintf("Execution exceeded the limit of %d steps.", b.limits.MaxSteps))
+	}
+
+	if b.limits.MaxHeapBytes > 0 {
+		heap := readHeap()
+		if heap > b.heapBase && heap-b.heapBase > b.limits.MaxHeapBytes {
+			return errors.NewLimitError("heap", fmt.Sprintf("Execution exceeded the heap limit of %d bytes.", b.limits.MaxHeapBytes))
+		}
+	}
+	return nil
+}
+
+// enterCall counts a call made from paren. A call that nests too deeply
+// is not entered, so the caller must only call exitCall after a nil error.
+func (b *budget) enterCall(paren token.Token) error {
+	err := b.checkpoint()
+	if err != nil {
+		return err
+	}
+
+	if b.limits.MaxCallDepth > 0 && b.callDepth >= b.limits.MaxCallDepth {
+		return errors.NewLimitError("call depth", fmt.Sprintf("Execution exceeded the call depth limit of %d.", b.limits.MaxCallDepth))
+	}
+	if b.callDepth >= maxStackDepth {
+		return errors.NewStackOverflowError(paren)
+	}
+	b.callDepth++
+	return nil
+}
+
+func (b *budget) exitCall() {
+	b.callDepth--
+}
@@ -0,0 +1,185 @@
+package visitor
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"time"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// floatFunction wraps a float64 function as a native taking one number of
+// any kind, which is converted to a float first.
+func floatFunction(name string, function func(float64) float64) *NativeFunction {
+	return NewNativeFunction(name, 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		err := checkNumberOperand(token.NewTokenNil(), arguments...)
+		if err != nil {
+			return nil, err
+		}
+		return function(toFloat(arguments[0])), nil
+	})
+}
+
+// rounding wraps floor or ceil. Exact kinds keep their kind, rounding a
+// decimal to an integral decimal; integers are returned unchanged.
+func rounding(name string, function func(float64) float64, up bool) *NativeFunction {
+	return NewNativeFunction(name, 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		err := checkNumberOperand(token.NewTokenNil(), arguments...)
+		if err != nil {
+			return nil, err
+		}
+		switch value := arguments[0].(type) {
+		case float64:
+			return function(value), nil
+		case decimal.Decimal:
+			// Int truncates towards zero, which only needs adjusting for
+			// values with a fractional part on the side being rounded to.
+			integer := decimal.FromInt(value.Int())
+			if integer.Cmp(value) > 0 && !up {
+				return integer.Sub(decimal.FromInt(big.NewInt(1))), nil
+			}
+			if integer.Cmp(value) < 0 && up {
+				return integer.Add(decimal.FromInt(big.NewInt(1))), nil
+			}
+			return integer, nil
+		}
+		return arguments[0], nil
+	})
+}
+
+// extremum returns min or max over one or more numbers, keeping the kind
+// of the chosen argument.
+func extremum(name string, operator token.TokenType) *NativeFunction {
+	return NewNativeFunction(name, -1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		if len(arguments) == 0 {
+			return nil, errors.NewRuntimeError(token.NewTokenNil(), "Expected at least 1 argument but got 0.")
+		}
+		err := checkNumberOperand(token.NewTokenNil(), arguments...)
+		if err != nil {
+			return nil, err
+		}
+		result := arguments[0]
+		for _, argument := range arguments[1:] {
+			if compare(operator, argument, result) {
+				result = argument
+			}
+		}
+		return result, nil
+	})
+}
+
+// integerArgument accepts an int64 or a float with an integral value, as
+// every number is a float when integer literals are disabled.
+func integerArgument(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return number, true
+	case float64:
+		if number == math.Trunc(number) && math.Abs(number) < 1<<63 {
+			return int64(number), true
+		}
+	}
+	return 0, false
+}
+
+// random is the generator behind the math module's random functions,
+// seeded from the clock until a script calls math.seed(n). It is shared by
+// an interpreter and the modules it imports, so a seed set anywhere makes
+// every module's draws reproducible.
+type random struct {
+	source    *rand.PCG
+	generator *rand.Rand
+}
+
+func newRandom() *random {
+	source := rand.NewPCG(uint64(time.Now().UnixNano()), 0)
+	return &random{
+		source:    source,
+		generator: rand.New(source),
+	}
+}
+
+// newMathModule builds the math module.
+func newMathModule() *Module {
+	return newNativeModule("math", map[string]interface{}{
+		"pi": math.Pi,
+		"e":  math.E,
+
+		"sqrt":  floatFunction("sqrt", math.Sqrt),
+		"sin":   floatFunction("sin", math.Sin),
+		"cos":   floatFunction("cos", math.Cos),
+		"tan":   floatFunction("tan", math.Tan),
+		"asin":  floatFunction("asin", math.Asin),
+		"acos":  floatFunction("acos", math.Acos),
+		"atan":  floatFunction("atan", math.Atan),
+		"log":   floatFunction("log", math.Log),
+		"log10": floatFunction("log10", math.Log10),
+		"exp":   floatFunction("exp", math.Exp),
+		"floor": rounding("floor", math.Floor, false),
+		"ceil":  rounding("ceil", math.Ceil, true),
+		"min":   extremum("min", token.LESS),
+		"max":   extremum("max", token.GREATER),
+
+		"atan2": NewNativeFunction("atan2", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkNumberOperand(token.NewTokenNil(), arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return math.Atan2(toFloat(arguments[0]), toFloat(arguments[1])), nil
+		}),
+
+		// pow follows the ** operator, so exact bases stay exact.
+		"pow": NewNativeFunction("pow", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkNumberOperand(token.NewTokenNil(), arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return power(token.NewTokenNil(), arguments[0], arguments[1])
+		}),
+
+		"abs": NewNativeFunction("abs", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkNumberOperand(token.NewTokenNil(), arguments...)
+			if err != nil {
+				return nil, err
+			}
+			value := arguments[0]
+			if compare(token.LESS, value, int64(0)) {
+				return negate(value), nil
+			}
+			return value, nil
+		}),
+
+		"seed": NewNativeFunction("seed", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			seed, ok := integerArgument(arguments[0])
+			if !ok {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Seed must be an integer.")
+			}
+			interpreter.random.source.Seed(uint64(seed), 0)
+			return nil, nil
+		}),
+
+		// random returns a float in [0, 1).
+		"random": NewNativeFunction("random", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return interpreter.random.generator.Float64(), nil
+		}),
+
+		// randomInt returns an integer in [low, high).
+		"randomInt": NewNativeFunction("randomInt", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			low, lowOk := integerArgument(arguments[0])
+			high, highOk := integerArgument(arguments[1])
+			if !lowOk || !highOk {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Bounds must be integers.")
+			}
+			if low >= high {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Lower bound must be less than upper bound.")
+			}
+			// high-low can overflow int64, but not uint64, and adding the
+			// offset back wraps around to the right value.
+			offset := interpreter.random.generator.Uint64N(uint64(high) - uint64(low))
+			return low + int64(offset), nil
+		}),
+	})
+}
@@ -0,0 +1,196 @@
+package visitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/scanner"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Module is the value an import statement binds. Its properties are the
+// exported names of the module's globals, read when accessed so importers
+// see later assignments made inside the module.
+type Module struct {
+	Name    string
+	globals environment.Environment
+	exports map[string]bool
+}
+
+func (m *Module) Get(name token.Token) (interface{}, error) {
+	if !m.exports[name.Lexeme] {
+		return nil, errors.NewRuntimeError(name, "Module '"+m.Name+"' has no export '"+name.Lexeme+"'.")
+	}
+	return m.globals.Get(name)
+}
+
+func (m *Module) String() string {
+	return "<module " + m.Name + ">"
+}
+
+// newNativeModule builds a module implemented in Go, exporting every member.
+func newNativeModule(name string, members map[string]interface{}) *Module {
+	globals := environment.NewEnvironment(nil)
+	exports := map[string]bool{}
+	for member, value := range members {
+		globals.Define(member, value)
+		exports[member] = true
+	}
+	return &Module{
+		Name:    name,
+		globals: globals,
+		exports: exports,
+	}
+}
+
+// modules is shared by an interpreter and every module it imports, so each
+// file is loaded once however many modules import it.
+type modules struct {
+	// loaded maps absolute paths to modules that finished executing.
+	loaded map[string]*Module
+	// loading is the chain of absolute paths currently being imported,
+	// starting with the script being run.
+	loading []string
+	// root is the resolved directory of the script being run, empty when
+	// it has none.
+	root string
+	// integers is passed on to the scanner of every imported file. Like
+	// scanner.Scanner.Integers it is off unless SetIntegers enables it.
+	integers bool
+}
+
+func newModules() *modules {
+	return &modules{
+		loaded: map[string]*Module{},
+	}
+}
+
+// SetScriptPath records the file the interpreter runs. Import paths are
+// resolved against the importing file, and modules may only be loaded from
+// the script's directory tree or the directories allowed by SetAllowedDirs.
+// Without a script path, only allowed directories can be imported from.
+// A module importing the script itself is reported as an import cycle.
+func (interpreter *Interpreter) SetScriptPath(path string) {
+	interpreter.path = path
+	interpreter.modules.root = ""
+	interpreter.modules.loading = nil
+	if path == "" {
+		return
+	}
+	root, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+	resolved, err := filepath.EvalSymlinks(root)
+	if err == nil {
+		root = resolved
+	}
+	interpreter.modules.root = root
+
+	// Imports are recorded by resolved path, so the script is too.
+	script, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Base(path)))
+	if err == nil {
+		interpreter.modules.loading = []string{script}
+	}
+}
+
+// SetIntegers selects whether imported files scan literals without a
+// decimal point as integers, matching scanner.Scanner.Integers.
+func (interpreter *Interpreter) SetIntegers(integers bool) {
+	interpreter.modules.integers = integers
+}
+
+// importModule returns the module at path, relative to the importing file,
+// loading and executing it with its own globals the first time.
+func (interpreter *Interpreter) importModule(keyword token.Token, path string) (*Module, error) {
+	base := "."
+	if interpreter.path != "" {
+		base = filepath.Dir(interpreter.path)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	dirs := interpreter.files.allowed
+	if interpreter.modules.root != "" {
+		dirs = append([]string{interpreter.modules.root}, dirs...)
+	}
+	absolute, err := resolveWithin(path, dirs)
+	if err != nil {
+		return nil, errors.NewRuntimeError(keyword, "Access to module '"+path+"' is not allowed.")
+	}
+
+	if module, ok := interpreter.modules.loaded[absolute]; ok {
+		return module, nil
+	}
+	for i, loading := range interpreter.modules.loading {
+		if loading == absolute {
+			chain := append(append([]string{}, interpreter.modules.loading[i:]...), absolute)
+			for j := range chain {
+				chain[j] = filepath.Base(chain[j])
+			}
+			return nil, errors.NewRuntimeError(keyword, "Import cycle: "+strings.Join(chain, " -> ")+".")
+		}
+	}
+
+	source, err := os.ReadFile(absolute)
+	if err != nil {
+		return nil, errors.NewRuntimeError(keyword, "Could not read module '"+path+"'.")
+	}
+	s := scanner.NewScanner(string(source))
+	s.Integers = interpreter.modules.integers
+	errs := s.ScanTokens()
+	if len(errs) > 0 {
+		return nil, errors.NewRuntimeError(keyword, "Error in module '"+path+"': "+errs[0].Error())
+	}
+	p := parser.New(s.Tokens)
+	statements, err := p.Parse()
+	if err != nil {
+		return nil, errors.NewRuntimeError(keyword, "Error in module '"+path+"': "+err.Error())
+	}
+
+	child := NewInterpreter()
+	child.budget = interpreter.budget
+	child.modules = interpreter.modules
+	child.files = interpreter.files
+	child.patterns = interpreter.patterns
+	child.random = interpreter.random
+	child.clock = interpreter.clock
+	child.system = interpreter.system
+	child.path = absolute
+	child.file = absolute
+	if interpreter.modules.root != "" {
+		relative, err := filepath.Rel(interpreter.modules.root, absolute)
+		if err == nil {
+			child.file = relative
+		}
+	}
+
+	interpreter.modules.loading = append(interpreter.modules.loading, absolute)
+	defer func() {
+		interpreter.modules.loading = interpreter.modules.loading[:len(interpreter.modules.loading)-1]
+	}()
+	for _, statement := range statements {
+		err := child.execute(statement)
+		if runtimeError, ok := err.(errors.RuntimeError); ok {
+			frame := errors.StackFrame{Line: keyword.Line, File: child.file}
+			runtimeError.Stack = append(runtimeError.Stack, frame)
+			return nil, runtimeError
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(absolute), filepath.Ext(absolute))
+	module := &Module{
+		Name:    name,
+		globals: *child.globals,
+		exports: child.exports,
+	}
+	interpreter.modules.loaded[absolute] = module
+	return module, nil
+}
@@ -0,0 +1,574 @@
+package visitor
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Numeric kinds in promotion order: an operation on two exact kinds is
+// carried out in the wider one. Floats mix with int64, but only integral
+// floats mix with big integers or decimals; see exactOperands.
+const (
+	intKind = iota
+	bigIntKind
+	decimalKind
+	floatKind
+	notNumber
+)
+
+func numberKind(value interface{}) int {
+	switch value.(type) {
+	case int64:
+		return intKind
+	case *big.Int:
+		return bigIntKind
+	case decimal.Decimal:
+		return decimalKind
+	case float64:
+		return floatKind
+	}
+	return notNumber
+}
+
+func isNumber(value interface{}) bool {
+	return numberKind(value) != notNumber
+}
+
+// toFloat converts any number to the nearest float64.
+func toFloat(value interface{}) float64 {
+	switch value := value.(type) {
+	case int64:
+		return float64(value)
+	case *big.Int:
+		result, _ := new(big.Float).SetInt(value).Float64()
+		return result
+	case decimal.Decimal:
+		return value.Float64()
+	case float64:
+		return value
+	}
+	return math.NaN()
+}
+
+func toBigInt(value interface{}) *big.Int {
+	switch value := value.(type) {
+	case int64:
+		return big.NewInt(value)
+	case *big.Int:
+		return value
+	}
+	return nil
+}
+
+func toDecimal(value interface{}) decimal.Decimal {
+	if value, ok := value.(decimal.Decimal); ok {
+		return value
+	}
+	return decimal.FromInt(toBigInt(value))
+}
+
+// toRat converts any number exactly, or returns nil for NaN and infinities.
+func toRat(value interface{}) *big.Rat {
+	switch value := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(value)
+	case *big.Int:
+		return new(big.Rat).SetInt(value)
+	case decimal.Decimal:
+		return value.Rat()
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil
+		}
+		return new(big.Rat).SetFloat64(value)
+	}
+	return nil
+}
+
+// exactOperands converts an integral float combined with a big integer or
+// decimal to a big integer, which is exact, so that plain literals work
+// with exact numbers when every literal is a float. Any other float would
+// lose precision and needs an explicit conversion.
+func exactOperands(operator token.Token, left interface{}, right interface{}) (interface{}, interface{}, error) {
+	isExact := func(value interface{}) bool {
+		kind := numberKind(value)
+		return kind == bigIntKind || kind == decimalKind
+	}
+	convert := func(value interface{}) (interface{}, error) {
+		float := value.(float64)
+		if math.IsInf(float, 0) || float != math.Trunc(float) {
+			return nil, errors.NewRuntimeError(operator, "Can't mix non-integral floats with big integers or decimals; convert one explicitly.")
+		}
+		return toRat(float).Num(), nil
+	}
+
+	var err error
+	if numberKind(left) == floatKind && isExact(right) {
+		left, err = convert(left)
+	} else if numberKind(right) == floatKind && isExact(left) {
+		right, err = convert(right)
+	}
+	return left, right, err
+}
+
+// intOverflows reports whether applying operator to two int64s would wrap
+// around instead of giving the exact result.
+func intOverflows(operator token.TokenType, l int64, r int64) bool {
+	switch operator {
+	case token.PLUS:
+		sum := l + r
+		return (r > 0 && sum < l) || (r < 0 && sum > l)
+	case token.MINUS:
+		difference := l - r
+		return (r > 0 && difference > l) || (r < 0 && difference < l)
+	case token.STAR:
+		if l == 0 || r == 0 {
+			return false
+		}
+		if (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+			return true
+		}
+		return (l*r)/r != l
+	case token.SLASH:
+		return l == math.MinInt64 && r == -1
+	}
+	return false
+}
+
+// arithmetic applies a numeric binary operator. Two integers give an
+// integer result, truncating on division, or a big integer when the result
+// doesn't fit an int64; big integers and decimals promote as described
+// above, and a float operand makes the result a float.
+func arithmetic(operator token.Token, left interface{}, right interface{}) (interface{}, error) {
+	left, right, err := exactOperands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+	leftKind, rightKind := numberKind(left), numberKind(right)
+	kind := max(leftKind, rightKind)
+
+	isDivision := operator.TokenType == token.SLASH || operator.TokenType == token.PERCENT
+	if isDivision && kind != floatKind && toRat(right).Sign() == 0 {
+		return nil, errors.NewRuntimeError(operator, "Division by zero.")
+	}
+
+	if kind == intKind && intOverflows(operator.TokenType, left.(int64), right.(int64)) {
+		kind = bigIntKind
+	}
+
+	switch kind {
+	case intKind:
+		l, r := left.(int64), right.(int64)
+		switch operator.TokenType {
+		case token.PLUS:
+			return l + r, nil
+		case token.MINUS:
+			return l - r, nil
+		case token.STAR:
+			return l * r, nil
+		case token.SLASH:
+			return l / r, nil
+		case token.PERCENT:
+			return l % r, nil
+		}
+	case bigIntKind:
+		l, r := toBigInt(left), toBigInt(right)
+		switch operator.TokenType {
+		case token.PLUS:
+			return new(big.Int).Add(l, r), nil
+		case token.MINUS:
+			return new(big.Int).Sub(l, r), nil
+		case token.STAR:
+			return new(big.Int).Mul(l, r), nil
+		case token.SLASH:
+			return new(big.Int).Quo(l, r), nil
+		case token.PERCENT:
+			return new(big.Int).Rem(l, r), nil
+		}
+	case decimalKind:
+		l, r := toDecimal(left), toDecimal(right)
+		switch operator.TokenType {
+		case token.PLUS:
+			return l.Add(r), nil
+		case token.MINUS:
+			return l.Sub(r), nil
+		case token.STAR:
+			return l.Mul(r), nil
+		case token.SLASH:
+			return l.Quo(r), nil
+		case token.PERCENT:
+			return l.Rem(r), nil
+		}
+	case floatKind:
+		l, r := toFloat(left), toFloat(right)
+		switch operator.TokenType {
+		case token.PLUS:
+			return l + r, nil
+		case token.MINUS:
+			return l - r, nil
+		case token.STAR:
+			return l * r, nil
+		case token.SLASH:
+			return l / r, nil
+		case token.PERCENT:
+			return math.Mod(l, r), nil
+		}
+	}
+	return nil, errors.NewRuntimeError(operator, "Unknown arithmetic operator.")
+}
+
+// maxPowerBits bounds the size of exact results of **, which are allocated
+// by a single operation that the heap limit can't interrupt.
+const maxPowerBits = 1 << 20
+
+// powerTooLarge reports whether raising a base of bits bits to the n would
+// give a result of more than maxPowerBits bits. Bases of at most one bit,
+// 0, 1 and -1, never grow.
+func powerTooLarge(bits int, n int64) bool {
+	if bits <= 1 {
+		return false
+	}
+	if n < 0 {
+		n = -n
+	}
+	return n < 0 || n > maxPowerBits/int64(bits)
+}
+
+// power raises left to the integer or float right. Integer exponents keep
+// exact bases exact; a negative exponent on an int64 gives a float, as it
+// would for any float operand.
+func power(operator token.Token, left interface{}, right interface{}) (interface{}, error) {
+	left, right, err := exactOperands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+	leftKind, rightKind := numberKind(left), numberKind(right)
+	kind := max(leftKind, rightKind)
+	if kind == floatKind {
+		return math.Pow(toFloat(left), toFloat(right)), nil
+	}
+	if rightKind == decimalKind {
+		return nil, errors.NewRuntimeError(operator, "Exponent of a big integer or decimal must be an integer.")
+	}
+
+	exponent := toBigInt(right)
+	if leftKind == intKind && exponent.Sign() < 0 {
+		return math.Pow(toFloat(left), toFloat(right)), nil
+	}
+
+	var bits int
+	if leftKind == decimalKind {
+		bits = left.(decimal.Decimal).BitLen()
+	} else {
+		bits = toBigInt(left).BitLen()
+	}
+	if !exponent.IsInt64() {
+		return nil, errors.NewRuntimeError(operator, "Exponent is too large.")
+	}
+	if powerTooLarge(bits, exponent.Int64()) {
+		return nil, errors.NewRuntimeError(operator, "Result of ** is too large.")
+	}
+	n := exponent.Int64()
+
+	switch leftKind {
+	case intKind:
+		// Results too large for an int64 become big integers.
+		result := new(big.Int).Exp(toBigInt(left), big.NewInt(n), nil)
+		if result.IsInt64() {
+			return result.Int64(), nil
+		}
+		return result, nil
+	case bigIntKind:
+		result := new(big.Int).Exp(left.(*big.Int), big.NewInt(max(n, -n)), nil)
+		if n < 0 {
+			return decimal.FromInt(big.NewInt(1)).Quo(decimal.FromInt(result)), nil
+		}
+		return result, nil
+	}
+
+	base, result := left.(decimal.Decimal), decimal.FromInt(big.NewInt(1))
+	for i := max(n, -n); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			result = result.Mul(base)
+		}
+		base = base.Mul(base)
+	}
+	if n < 0 {
+		if result.Sign() == 0 {
+			return nil, errors.NewRuntimeError(operator, "Division by zero.")
+		}
+		return decimal.FromInt(big.NewInt(1)).Quo(result), nil
+	}
+	return result, nil
+}
+
+// toInteger accepts int64 and big integer operands of bitwise operators,
+// and integral floats so that they also work when every number is a float.
+func toInteger(operator token.Token, value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case int64, *big.Int:
+		return value, nil
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<63 {
+			return int64(value), nil
+		}
+	}
+	return nil, errors.NewRuntimeError(operator, "Operands must be integers.")
+}
+
+// maxShift bounds shifts of big integers, which allocate the shifted value.
+const maxShift = 1 << 20
+
+// bitwise applies & | ^ << and >>. Two int64 operands give an int64, or a
+// big integer when a left shift would lose bits; a big integer operand
+// makes the result a big integer.
+func bitwise(operator token.Token, left interface{}, right interface{}) (interface{}, error) {
+	l, err := toInteger(operator, left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := toInteger(operator, right)
+	if err != nil {
+		return nil, err
+	}
+
+	isShift := operator.TokenType == token.LESS_LESS || operator.TokenType == token.GREATER_GREATER
+	if isShift && toBigInt(r).Sign() < 0 {
+		return nil, errors.NewRuntimeError(operator, "Shift count must not be negative.")
+	}
+
+	li, leftIsInt := l.(int64)
+	ri, rightIsInt := r.(int64)
+	if leftIsInt && rightIsInt {
+		switch operator.TokenType {
+		case token.AMPERSAND:
+			return li & ri, nil
+		case token.PIPE:
+			return li | ri, nil
+		case token.CARET:
+			return li ^ ri, nil
+		case token.LESS_LESS:
+			// Shifts that lose bits are done on big integers below.
+			if ri < 64 && (li<<ri)>>ri == li {
+				return li << ri, nil
+			}
+		case token.GREATER_GREATER:
+			return li >> ri, nil
+		}
+	}
+
+	bl, br := toBigInt(l), toBigInt(r)
+	switch operator.TokenType {
+	case token.AMPERSAND:
+		return new(big.Int).And(bl, br), nil
+	case token.PIPE:
+		return new(big.Int).Or(bl, br), nil
+	case token.CARET:
+		return new(big.Int).Xor(bl, br), nil
+	}
+
+	if br.Cmp(big.NewInt(maxShift)) > 0 {
+		return nil, errors.NewRuntimeError(operator, "Shift count is too large.")
+	}
+	if operator.TokenType == token.LESS_LESS {
+		return new(big.Int).Lsh(bl, uint(br.Uint64())), nil
+	}
+	return new(big.Int).Rsh(bl, uint(br.Uint64())), nil
+}
+
+func complement(operator token.Token, value interface{}) (interface{}, error) {
+	integer, err := toInteger(operator, value)
+	if err != nil {
+		return nil, err
+	}
+	if integer, ok := integer.(int64); ok {
+		return ^integer, nil
+	}
+	return new(big.Int).Not(integer.(*big.Int)), nil
+}
+
+// compare applies a numeric comparison operator. Numbers of different
+// kinds are compared exactly; NaN is unordered and unequal to everything.
+func compare(operator token.TokenType, left interface{}, right interface{}) bool {
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			return compareFloats(operator, l, r)
+		}
+	}
+
+	l, r := toRat(left), toRat(right)
+	if l == nil || r == nil {
+		return compareFloats(operator, toFloat(left), toFloat(right))
+	}
+	order := l.Cmp(r)
+
+	switch operator {
+	case token.GREATER:
+		return order > 0
+	case token.GREATER_EQUAL:
+		return order >= 0
+	case token.LESS:
+		return order < 0
+	case token.LESS_EQUAL:
+		return order <= 0
+	}
+	return order == 0
+}
+
+func compareFloats(operator token.TokenType, l float64, r float64) bool {
+	switch operator {
+	case token.GREATER:
+		return l > r
+	case token.GREATER_EQUAL:
+		return l >= r
+	case token.LESS:
+		return l < r
+	case token.LESS_EQUAL:
+		return l <= r
+	}
+	return l == r
+}
+
+func negate(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int64:
+		if value == math.MinInt64 {
+			return new(big.Int).Neg(toBigInt(value))
+		}
+		return -value
+	case *big.Int:
+		return new(big.Int).Neg(value)
+	case decimal.Decimal:
+		return value.Neg()
+	}
+	return -value.(float64)
+}
+
+// isEqual compares Lox values, treating numbers of different kinds with
+// the same value as equal.
+func isEqual(left interface{}, right interface{}) bool {
+	if isNumber(left) && isNumber(right) {
+		return compare(token.EQUAL_EQUAL, left, right)
+	}
+	return left == right
+}
+
+func conversionError(function string, value interface{}) error {
+	return errors.NewRuntimeError(token.NewTokenNil(), "Can't convert "+typeName(value)+" with "+function+"().")
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64, float64, *big.Int, decimal.Decimal:
+		return "number"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case *Module:
+		return "module"
+	case *Time:
+		return "time"
+	case Callable:
+		return "function"
+	}
+	return "object"
+}
+
+// defineNumberNatives registers the explicit conversions between the
+// numeric kinds. Each also parses a string in the kind's literal syntax.
+func defineNumberNatives(globals environment.Environment) {
+	globals.Define("int", NewNativeFunction("int", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		switch value := arguments[0].(type) {
+		case int64:
+			return value, nil
+		case float64:
+			if math.IsNaN(value) || math.IsInf(value, 0) || math.Abs(value) >= 1<<63 {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Number is out of integer range.")
+			}
+			return int64(value), nil
+		case *big.Int, decimal.Decimal:
+			integer := toBigInt(value)
+			if integer == nil {
+				integer = value.(decimal.Decimal).Int()
+			}
+			if !integer.IsInt64() {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Number is out of integer range.")
+			}
+			return integer.Int64(), nil
+		case string:
+			integer, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err == nil {
+				return integer, nil
+			}
+		}
+		return nil, conversionError("int", arguments[0])
+	}))
+
+	globals.Define("float", NewNativeFunction("float", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		if isNumber(arguments[0]) {
+			return toFloat(arguments[0]), nil
+		}
+		if value, ok := arguments[0].(string); ok {
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				return number, nil
+			}
+		}
+		return nil, conversionError("float", arguments[0])
+	}))
+
+	globals.Define("bigint", NewNativeFunction("bigint", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		switch value := arguments[0].(type) {
+		case int64, *big.Int:
+			return toBigInt(value), nil
+		case decimal.Decimal:
+			return value.Int(), nil
+		case float64:
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, conversionError("bigint", value)
+			}
+			integer, _ := new(big.Float).SetFloat64(math.Trunc(value)).Int(nil)
+			return integer, nil
+		case string:
+			integer, ok := new(big.Int).SetString(strings.TrimSpace(value), 10)
+			if ok {
+				return integer, nil
+			}
+		}
+		return nil, conversionError("bigint", arguments[0])
+	}))
+
+	globals.Define("decimal", NewNativeFunction("decimal", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		switch value := arguments[0].(type) {
+		case int64, *big.Int, decimal.Decimal:
+			return toDecimal(value), nil
+		case float64:
+			// The shortest representation that round-trips, so 0.1 becomes
+			// 0.1 rather than its exact binary expansion.
+			number, err := decimal.Parse(strconv.FormatFloat(value, 'f', -1, 64))
+			if err == nil {
+				return number, nil
+			}
+		case string:
+			number, err := decimal.Parse(strings.TrimSpace(value))
+			if err == nil {
+				return number, nil
+			}
+		}
+		return nil, conversionError("decimal", arguments[0])
+	}))
+}
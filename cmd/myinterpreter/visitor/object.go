@@ -0,0 +1,230 @@
+package visitor
+
+import (
+	"math"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Object is implemented by values whose properties can be read with a dot.
+type Object interface {
+	Get(name token.Token) (interface{}, error)
+}
+
+// ErrorObject is the value a catch clause binds for errors raised by the
+// interpreter itself rather than by a throw statement.
+type ErrorObject struct {
+	Message string
+	Line    int
+}
+
+func (eo *ErrorObject) Get(name token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "message":
+		return eo.Message, nil
+	case "line":
+		return float64(eo.Line), nil
+	}
+	return nil, errors.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func (eo *ErrorObject) String() string {
+	return eo.Message
+}
+
+func NewErrorObject(err errors.RuntimeError) *ErrorObject {
+	return &ErrorObject{
+		Message: err.Message,
+		Line:    err.Token.Line,
+	}
+}
+
+type List struct {
+	Elements []interface{}
+}
+
+func (l *List) Get(name token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "push":
+		return NewNativeFunction("push", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			l.Elements = append(l.Elements, arguments[0])
+			return nil, nil
+		}), nil
+	case "pop":
+		return NewNativeFunction("pop", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if len(l.Elements) == 0 {
+				return nil, errors.NewRuntimeError(name, "Can't pop from an empty list.")
+			}
+			last := l.Elements[len(l.Elements)-1]
+			l.Elements = l.Elements[:len(l.Elements)-1]
+			return last, nil
+		}), nil
+	case "join":
+		return NewNativeFunction("join", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("join", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			parts := make([]string, len(l.Elements))
+			for i, element := range l.Elements {
+				parts[i] = interpreter.Stringify(element)
+			}
+			return strings.Join(parts, arguments[0].(string)), nil
+		}), nil
+	}
+	return nil, errors.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func NewList(elements []interface{}) *List {
+	return &List{
+		Elements: elements,
+	}
+}
+
+// toIndex converts a Lox number to a position in a sequence of the given
+// length. Slice bounds may also equal length.
+func toIndex(t token.Token, value interface{}, length int, isBound bool) (int, error) {
+	var index int
+	switch number := value.(type) {
+	case int64:
+		if number < 0 || number > int64(length) {
+			return 0, errors.NewRuntimeError(t, "Index out of range.")
+		}
+		index = int(number)
+	case float64:
+		if number != math.Trunc(number) {
+			return 0, errors.NewRuntimeError(t, "Index must be an integer.")
+		}
+		if number < 0 || number > float64(length) {
+			return 0, errors.NewRuntimeError(t, "Index out of range.")
+		}
+		index = int(number)
+	default:
+		return 0, errors.NewRuntimeError(t, "Index must be an integer.")
+	}
+
+	if index < 0 || index > length || (index == length && !isBound) {
+		return 0, errors.NewRuntimeError(t, "Index out of range.")
+	}
+	return index, nil
+}
+
+// Map is an insertion-ordered dictionary keyed by strings, numbers and
+// booleans. Besides its methods, string keys that don't clash with a method
+// name can be read as properties.
+type Map struct {
+	keys   []interface{}
+	values map[interface{}]interface{}
+}
+
+func checkKey(t token.Token, key interface{}) error {
+	switch key.(type) {
+	case string, int64, float64, bool:
+		return nil
+	}
+	return errors.NewRuntimeError(t, "Map keys must be strings, numbers or booleans.")
+}
+
+// normalizeKey stores integral floats as integers so that 1 and 1.0 name
+// the same entry, matching ==.
+func normalizeKey(key interface{}) interface{} {
+	if number, ok := key.(float64); ok && number == math.Trunc(number) && math.Abs(number) < 1<<63 {
+		return int64(number)
+	}
+	return key
+}
+
+func (m *Map) Keys() []interface{} {
+	return m.keys
+}
+
+func (m *Map) Len() int {
+	return len(m.keys)
+}
+
+func (m *Map) Lookup(key interface{}) (interface{}, bool) {
+	value, ok := m.values[normalizeKey(key)]
+	return value, ok
+}
+
+func (m *Map) Set(key interface{}, value interface{}) {
+	key = normalizeKey(key)
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *Map) Delete(key interface{}) bool {
+	key = normalizeKey(key)
+	if _, ok := m.values[key]; !ok {
+		return false
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (m *Map) Get(name token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "get":
+		return NewNativeFunction("get", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkKey(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			value, _ := m.Lookup(arguments[0])
+			return value, nil
+		}), nil
+	case "set":
+		return NewNativeFunction("set", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkKey(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			m.Set(arguments[0], arguments[1])
+			return nil, nil
+		}), nil
+	case "delete":
+		return NewNativeFunction("delete", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkKey(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return m.Delete(arguments[0]), nil
+		}), nil
+	case "has":
+		return NewNativeFunction("has", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkKey(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			_, ok := m.Lookup(arguments[0])
+			return ok, nil
+		}), nil
+	case "keys":
+		return NewNativeFunction("keys", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			keys := make([]interface{}, len(m.keys))
+			copy(keys, m.keys)
+			return NewList(keys), nil
+		}), nil
+	}
+
+	if value, ok := m.Lookup(name.Lexeme); ok {
+		return value, nil
+	}
+	return nil, errors.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func NewMap() *Map {
+	return &Map{
+		values: make(map[interface{}]interface{}),
+	}
+}
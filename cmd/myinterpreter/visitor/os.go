@@ -0,0 +1,176 @@
+package visitor
+
+import (
+	"bytes"
+	"context"
+	goerrors "errors"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Capabilities selects what the os module may do. The zero value, the
+// default, allows nothing, and disabled functions raise runtime errors.
+type Capabilities struct {
+	// Args exposes the arguments given after the script name.
+	Args bool
+	// Env allows reading environment variables.
+	Env bool
+	// Exit allows os.exit to end the run with a status.
+	Exit bool
+	// Exec allows running subprocesses.
+	Exec bool
+}
+
+// AllCapabilities enables the whole os module.
+var AllCapabilities = Capabilities{Args: true, Env: true, Exit: true, Exec: true}
+
+// system holds the os module's configuration. It is shared by an
+// interpreter and the modules it imports.
+type system struct {
+	capabilities Capabilities
+	args         []string
+}
+
+// SetCapabilities replaces what the os module may do.
+func (interpreter *Interpreter) SetCapabilities(capabilities Capabilities) {
+	interpreter.system.capabilities = capabilities
+}
+
+// SetArgs sets the arguments os.args() returns.
+func (interpreter *Interpreter) SetArgs(args []string) {
+	interpreter.system.args = args
+}
+
+func disabled(function string) error {
+	return errors.NewRuntimeError(token.NewTokenNil(), "os."+function+"() is disabled.")
+}
+
+func stringList(values []string) *List {
+	elements := make([]interface{}, len(values))
+	for i, value := range values {
+		elements[i] = value
+	}
+	return NewList(elements)
+}
+
+func newOSModule() *Module {
+	return newNativeModule("os", map[string]interface{}{
+		"args": NewNativeFunction("args", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if !interpreter.system.capabilities.Args {
+				return nil, disabled("args")
+			}
+			return stringList(interpreter.system.args), nil
+		}),
+
+		// env returns the value of an environment variable, or nil when
+		// it isn't set.
+		"env": NewNativeFunction("env", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if !interpreter.system.capabilities.Env {
+				return nil, disabled("env")
+			}
+			err := checkStrings("env", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := os.LookupEnv(arguments[0].(string))
+			if !ok {
+				return nil, nil
+			}
+			return value, nil
+		}),
+
+		// environ returns every environment variable in a map sorted by name.
+		"environ": NewNativeFunction("environ", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if !interpreter.system.capabilities.Env {
+				return nil, disabled("environ")
+			}
+			variables := os.Environ()
+			sort.Strings(variables)
+			result := NewMap()
+			for _, variable := range variables {
+				name, value, _ := strings.Cut(variable, "=")
+				result.Set(name, value)
+			}
+			return result, nil
+		}),
+
+		"exit": NewNativeFunction("exit", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if !interpreter.system.capabilities.Exit {
+				return nil, disabled("exit")
+			}
+			code, ok := integerArgument(arguments[0])
+			if !ok || code < 0 || code > 255 {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Exit status must be an integer from 0 to 255.")
+			}
+			return nil, errors.NewExitError(int(code))
+		}),
+
+		// exec runs a program with a list of arguments, without a shell,
+		// and returns a map of its exit status, stdout and stderr. A
+		// non-zero status is not an error.
+		"exec": NewNativeFunction("exec", -1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			if !interpreter.system.capabilities.Exec {
+				return nil, disabled("exec")
+			}
+			err := checkArgumentRange(arguments, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := arguments[0].(string)
+			if !ok {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Program to exec() must be a string.")
+			}
+			var args []string
+			if len(arguments) == 2 {
+				list, ok := arguments[1].(*List)
+				if !ok {
+					return nil, errors.NewRuntimeError(token.NewTokenNil(), "Arguments to exec() must be a list of strings.")
+				}
+				for _, element := range list.Elements {
+					arg, ok := element.(string)
+					if !ok {
+						return nil, errors.NewRuntimeError(token.NewTokenNil(), "Arguments to exec() must be a list of strings.")
+					}
+					args = append(args, arg)
+				}
+			}
+
+			// The subprocess is killed if the run is cancelled or times out.
+			ctx := interpreter.budget.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			var stdout, stderr bytes.Buffer
+			command := exec.CommandContext(ctx, name, args...)
+			command.Stdout = &stdout
+			command.Stderr = &stderr
+			err = command.Run()
+
+			status := 0
+			var exitError *exec.ExitError
+			var lookupError *exec.Error
+			if goerrors.As(err, &exitError) {
+				status = exitError.ExitCode()
+			} else if goerrors.As(err, &lookupError) {
+				return nil, ioError("run", name, lookupError.Err)
+			} else if err != nil {
+				return nil, ioError("run", name, err)
+			}
+			err = interpreter.budget.checkpoint()
+			if err != nil {
+				return nil, err
+			}
+
+			result := NewMap()
+			result.Set("status", int64(status))
+			result.Set("stdout", stdout.String())
+			result.Set("stderr", stderr.String())
+			return result, nil
+		}),
+	})
+}
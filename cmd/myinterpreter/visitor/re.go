@@ -0,0 +1,133 @@
+package visitor
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// maxPatterns bounds the compiled pattern cache so scripts building
+// patterns dynamically can't grow it without limit.
+const maxPatterns = 256
+
+// patterns caches compiled regular expressions by source. It is shared by
+// an interpreter and the modules it imports.
+type patterns struct {
+	compiled map[string]*regexp.Regexp
+}
+
+func newPatterns() *patterns {
+	return &patterns{
+		compiled: map[string]*regexp.Regexp{},
+	}
+}
+
+func (p *patterns) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := p.compiled[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		message := strings.TrimPrefix(err.Error(), "error parsing regexp: ")
+		return nil, errors.NewRuntimeError(token.NewTokenNil(), "Invalid regular expression: "+message+".")
+	}
+	if len(p.compiled) >= maxPatterns {
+		clear(p.compiled)
+	}
+	p.compiled[pattern] = re
+	return re, nil
+}
+
+// regexArguments checks that a re native got string arguments and compiles
+// the first, the pattern.
+func (interpreter *Interpreter) regexArguments(function string, arguments []interface{}) (*regexp.Regexp, error) {
+	err := checkStrings(function, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.patterns.compile(arguments[0].(string))
+}
+
+// newMatch describes the match at indices, as returned by
+// FindStringSubmatchIndex, as a map of the matched text, its start and
+// end, the list of capture groups and a map of the named ones. Positions
+// count runes, like string indexing. Groups that took no part in the match
+// are nil.
+func newMatch(re *regexp.Regexp, str string, indices []int) *Map {
+	runeIndex := func(byteIndex int) int64 {
+		return int64(utf8.RuneCountInString(str[:byteIndex]))
+	}
+
+	groups := make([]interface{}, re.NumSubexp())
+	named := NewMap()
+	for i, name := range re.SubexpNames()[1:] {
+		start, end := indices[2*(i+1)], indices[2*(i+1)+1]
+		if start >= 0 {
+			groups[i] = str[start:end]
+		}
+		if name != "" {
+			named.Set(name, groups[i])
+		}
+	}
+
+	match := NewMap()
+	match.Set("match", str[indices[0]:indices[1]])
+	match.Set("start", runeIndex(indices[0]))
+	match.Set("end", runeIndex(indices[1]))
+	match.Set("groups", NewList(groups))
+	match.Set("named", named)
+	return match
+}
+
+func newRegexModule() *Module {
+	return newNativeModule("re", map[string]interface{}{
+		// match reports whether the pattern matches anywhere in the string.
+		"match": NewNativeFunction("match", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			re, err := interpreter.regexArguments("match", arguments)
+			if err != nil {
+				return nil, err
+			}
+			return re.MatchString(arguments[1].(string)), nil
+		}),
+
+		// find returns the first match, or nil when there is none.
+		"find": NewNativeFunction("find", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			re, err := interpreter.regexArguments("find", arguments)
+			if err != nil {
+				return nil, err
+			}
+			str := arguments[1].(string)
+			indices := re.FindStringSubmatchIndex(str)
+			if indices == nil {
+				return nil, nil
+			}
+			return newMatch(re, str, indices), nil
+		}),
+
+		"findAll": NewNativeFunction("findAll", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			re, err := interpreter.regexArguments("findAll", arguments)
+			if err != nil {
+				return nil, err
+			}
+			str := arguments[1].(string)
+			matches := []interface{}{}
+			for _, indices := range re.FindAllStringSubmatchIndex(str, -1) {
+				matches = append(matches, newMatch(re, str, indices))
+			}
+			return NewList(matches), nil
+		}),
+
+		// replace substitutes every match. The replacement can refer to
+		// groups as $1 or ${name}, written "\${name}" in a Lox string.
+		"replace": NewNativeFunction("replace", 3, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			re, err := interpreter.regexArguments("replace", arguments)
+			if err != nil {
+				return nil, err
+			}
+			return re.ReplaceAllString(arguments[1].(string), arguments[2].(string)), nil
+		}),
+	})
+}
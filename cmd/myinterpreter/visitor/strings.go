@@ -0,0 +1,157 @@
+package visitor
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// maxRepeatBytes bounds the length of a string built by repeat, which is
+// allocated at once and so can't be stopped by the heap limit.
+const maxRepeatBytes = 64 << 20
+
+// checkStrings reports an error unless every argument of the named method
+// is a string.
+func checkStrings(method string, arguments ...interface{}) error {
+	for _, argument := range arguments {
+		if _, ok := argument.(string); !ok {
+			return errors.NewRuntimeError(token.NewTokenNil(), "Argument to "+method+"() must be a string.")
+		}
+	}
+	return nil
+}
+
+// stringMethod returns the method of str named by name. Positions taken and
+// returned by the methods count runes, like indexing and len().
+func stringMethod(name token.Token, str string) (interface{}, error) {
+	switch name.Lexeme {
+	case "len":
+		return NewNativeFunction("len", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return int64(utf8.RuneCountInString(str)), nil
+		}), nil
+	case "substr":
+		// substr(start) runs to the end of the string; substr(start, end)
+		// stops before end.
+		return NewNativeFunction("substr", -1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkArgumentRange(arguments, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			runes := []rune(str)
+			start, err := toIndex(token.NewTokenNil(), arguments[0], len(runes), true)
+			if err != nil {
+				return nil, err
+			}
+			end := len(runes)
+			if len(arguments) == 2 {
+				end, err = toIndex(token.NewTokenNil(), arguments[1], len(runes), true)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if start > end {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Substring start must not be after its end.")
+			}
+			return string(runes[start:end]), nil
+		}), nil
+	case "indexOf":
+		return NewNativeFunction("indexOf", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("indexOf", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			index := strings.Index(str, arguments[0].(string))
+			if index < 0 {
+				return int64(-1), nil
+			}
+			return int64(utf8.RuneCountInString(str[:index])), nil
+		}), nil
+	case "split":
+		return NewNativeFunction("split", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("split", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			parts := strings.Split(str, arguments[0].(string))
+			elements := make([]interface{}, len(parts))
+			for i, part := range parts {
+				elements[i] = part
+			}
+			return NewList(elements), nil
+		}), nil
+	case "trim":
+		return NewNativeFunction("trim", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return strings.TrimSpace(str), nil
+		}), nil
+	case "upper":
+		return NewNativeFunction("upper", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return strings.ToUpper(str), nil
+		}), nil
+	case "lower":
+		return NewNativeFunction("lower", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return strings.ToLower(str), nil
+		}), nil
+	case "replace":
+		return NewNativeFunction("replace", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("replace", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return strings.ReplaceAll(str, arguments[0].(string), arguments[1].(string)), nil
+		}), nil
+	case "startsWith":
+		return NewNativeFunction("startsWith", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("startsWith", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasPrefix(str, arguments[0].(string)), nil
+		}), nil
+	case "endsWith":
+		return NewNativeFunction("endsWith", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("endsWith", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasSuffix(str, arguments[0].(string)), nil
+		}), nil
+	case "repeat":
+		return NewNativeFunction("repeat", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			count, ok := integerArgument(arguments[0])
+			if !ok || count < 0 {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Repeat count must be a non-negative integer.")
+			}
+			if count > 0 && int64(len(str)) > maxRepeatBytes/count {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Repeated string would be too long.")
+			}
+			return strings.Repeat(str, int(count)), nil
+		}), nil
+	}
+	return nil, errors.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+// defineStringNatives registers str() and num(), the conversions between
+// strings and other values.
+func defineStringNatives(globals environment.Environment) {
+	globals.Define("str", NewNativeFunction("str", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		return interpreter.Stringify(arguments[0]), nil
+	}))
+
+	globals.Define("num", NewNativeFunction("num", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		if isNumber(arguments[0]) {
+			return arguments[0], nil
+		}
+		if value, ok := arguments[0].(string); ok {
+			number, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				return number, nil
+			}
+			return nil, errors.NewRuntimeError(token.NewTokenNil(), "Can't convert '"+value+"' to a number.")
+		}
+		return nil, conversionError("num", arguments[0])
+	}))
+}
@@ -0,0 +1,259 @@
+package visitor
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Clock is the time source behind clock() and the time module. Embedders
+// replace it with SetClock, for example with a FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, returning early once ctx is done.
+	Sleep(ctx context.Context, d time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// FakeClock is a Clock whose time only moves when a script sleeps or the
+// embedder calls Advance, so runs are deterministic.
+type FakeClock struct {
+	now time.Time
+}
+
+func (c *FakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) {
+	c.Advance(d)
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{
+		now: start,
+	}
+}
+
+// SetClock replaces the time source of clock() and the time module.
+func (interpreter *Interpreter) SetClock(clock Clock) {
+	interpreter.clock = clock
+}
+
+// Time is a point in time as seen by scripts. Durations are plain numbers
+// of milliseconds.
+type Time struct {
+	Value time.Time
+}
+
+func toMilliseconds(d time.Duration) int64 {
+	return d.Milliseconds()
+}
+
+// durationArgument converts a number of milliseconds, which may have a
+// fractional part, to a time.Duration.
+func durationArgument(value interface{}) (time.Duration, error) {
+	err := checkNumberOperand(token.NewTokenNil(), value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(toFloat(value) * float64(time.Millisecond)), nil
+}
+
+func timeArgument(method string, value interface{}) (*Time, error) {
+	t, ok := value.(*Time)
+	if !ok {
+		return nil, errors.NewRuntimeError(token.NewTokenNil(), "Argument to "+method+"() must be a time.")
+	}
+	return t, nil
+}
+
+func (t *Time) Get(name token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "year":
+		return int64(t.Value.Year()), nil
+	case "month":
+		return int64(t.Value.Month()), nil
+	case "day":
+		return int64(t.Value.Day()), nil
+	case "hour":
+		return int64(t.Value.Hour()), nil
+	case "minute":
+		return int64(t.Value.Minute()), nil
+	case "second":
+		return int64(t.Value.Second()), nil
+	case "millisecond":
+		return int64(t.Value.Nanosecond() / int(time.Millisecond)), nil
+	case "weekday":
+		return t.Value.Weekday().String(), nil
+	case "unix":
+		return t.Value.UnixMilli(), nil
+	case "format":
+		return NewNativeFunction("format", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("format", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return t.Value.Format(arguments[0].(string)), nil
+		}), nil
+	case "add":
+		return NewNativeFunction("add", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			d, err := durationArgument(arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return &Time{Value: t.Value.Add(d)}, nil
+		}), nil
+	case "sub":
+		// sub returns the milliseconds from other to t.
+		return NewNativeFunction("sub", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			other, err := timeArgument("sub", arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return toMilliseconds(t.Value.Sub(other.Value)), nil
+		}), nil
+	case "before", "after", "equal":
+		return NewNativeFunction(name.Lexeme, 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			other, err := timeArgument(name.Lexeme, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			switch name.Lexeme {
+			case "before":
+				return t.Value.Before(other.Value), nil
+			case "after":
+				return t.Value.After(other.Value), nil
+			}
+			return t.Value.Equal(other.Value), nil
+		}), nil
+	case "utc":
+		return NewNativeFunction("utc", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return &Time{Value: t.Value.UTC()}, nil
+		}), nil
+	case "local":
+		return NewNativeFunction("local", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return &Time{Value: t.Value.Local()}, nil
+		}), nil
+	}
+	return nil, errors.NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func (t *Time) String() string {
+	return t.Value.Format(time.RFC3339Nano)
+}
+
+// newTimeModule builds the time module. Layouts for format and parse use
+// Go's reference time, Mon Jan 2 15:04:05 MST 2006, and the common ones are
+// provided as constants.
+func newTimeModule() *Module {
+	return newNativeModule("time", map[string]interface{}{
+		"RFC3339":  time.RFC3339,
+		"DateTime": time.DateTime,
+		"DateOnly": time.DateOnly,
+		"TimeOnly": time.TimeOnly,
+		"Kitchen":  time.Kitchen,
+
+		"millisecond": int64(1),
+		"second":      toMilliseconds(time.Second),
+		"minute":      toMilliseconds(time.Minute),
+		"hour":        toMilliseconds(time.Hour),
+
+		"now": NewNativeFunction("now", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			return &Time{Value: interpreter.clock.Now()}, nil
+		}),
+
+		// fromUnix returns the time a number of milliseconds after the
+		// Unix epoch, the inverse of the unix property.
+		"fromUnix": NewNativeFunction("fromUnix", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			d, err := durationArgument(arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return &Time{Value: time.UnixMilli(0).Add(d)}, nil
+		}),
+
+		"format": NewNativeFunction("format", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			t, err := timeArgument("format", arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			err = checkStrings("format", arguments[1])
+			if err != nil {
+				return nil, err
+			}
+			return t.Value.Format(arguments[1].(string)), nil
+		}),
+
+		// parse reads a time in layout. Times without a zone are UTC.
+		"parse": NewNativeFunction("parse", 2, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("parse", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			value, err := time.Parse(arguments[0].(string), arguments[1].(string))
+			if err != nil {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Can't parse time "+strconv.Quote(arguments[1].(string))+" with layout "+strconv.Quote(arguments[0].(string))+".")
+			}
+			return &Time{Value: value}, nil
+		}),
+
+		// duration converts text such as "1h30m" to milliseconds.
+		"duration": NewNativeFunction("duration", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			err := checkStrings("duration", arguments...)
+			if err != nil {
+				return nil, err
+			}
+			d, err := time.ParseDuration(arguments[0].(string))
+			if err != nil {
+				return nil, errors.NewRuntimeError(token.NewTokenNil(), "Invalid duration "+strconv.Quote(arguments[0].(string))+".")
+			}
+			return toMilliseconds(d), nil
+		}),
+
+		// formatDuration renders milliseconds as text such as "1h30m0s".
+		"formatDuration": NewNativeFunction("formatDuration", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			d, err := durationArgument(arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return d.String(), nil
+		}),
+
+		// sleep pauses the script for a number of milliseconds. Cancelling
+		// the run or reaching its timeout cuts the sleep short.
+		"sleep": NewNativeFunction("sleep", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+			d, err := durationArgument(arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			ctx := interpreter.budget.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			interpreter.clock.Sleep(ctx, d)
+			return nil, interpreter.budget.checkpoint()
+		}),
+	})
+}
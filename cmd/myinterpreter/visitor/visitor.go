@@ -1,9 +1,14 @@
 package visitor
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/decimal"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expr"
@@ -22,23 +27,583 @@ import (
  ****************/
 type Interpreter struct {
 	environment environment.Environment
+	globals     *environment.Environment
+	budget      *budget
+	// path is the file being run, empty when the source has none.
+	path string
+	// file names the module being run in error reports, relative to the
+	// script's directory, and is empty for the script itself.
+	file     string
+	exports  map[string]bool
+	modules  *modules
+	files    *files
+	patterns *patterns
+	random   *random
+	clock    Clock
+	system   *system
 }
 
 func (interpreter *Interpreter) executeBlock(statements []interfaces.Statement, env environment.Environment) error {
+	previous := interpreter.environment
 	interpreter.environment = env
-	var err error
+	defer func() {
+		interpreter.environment = previous
+	}()
 
 	for _, statement := range statements {
-		err = interpreter.execute(statement)
+		err := interpreter.execute(statement)
 		if err != nil {
 			return err
 		}
 	}
-
-	interpreter.environment = *env.Enclosing
 	return nil
 }
 
+func (interpreter *Interpreter) VisitIfStatement(ifStmt interfaces.Statement) (interface{}, error) {
+	ifStatement := ifStmt.(statements.IfStatement)
+	condition, err := interpreter.evaluate(ifStatement.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if functions.IsTruthy(condition) {
+		return nil, interpreter.execute(ifStatement.ThenBranch)
+	} else if ifStatement.ElseBranch != nil {
+		return nil, interpreter.execute(ifStatement.ElseBranch)
+	}
+	return nil, nil
+}
+
+func (interpreter *Interpreter) VisitWhileStatement(whileStmt interfaces.Statement) (interface{}, error) {
+	whileStatement := whileStmt.(statements.WhileStatement)
+	for {
+		condition, err := interpreter.evaluate(whileStatement.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if !functions.IsTruthy(condition) {
+			return nil, nil
+		}
+
+		err = interpreter.execute(whileStatement.Body)
+		switch err.(type) {
+		case nil, continueSignal:
+		case breakSignal:
+			return nil, nil
+		default:
+			return nil, err
+		}
+
+		if whileStatement.Increment != nil {
+			_, err = interpreter.evaluate(whileStatement.Increment)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = interpreter.budget.checkpoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (interpreter *Interpreter) VisitForInStatement(forInStmt interfaces.Statement) (interface{}, error) {
+	forIn := forInStmt.(statements.ForInStatement)
+	iterable, err := interpreter.evaluate(forIn.Iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := interpreter.iterate(forIn.Keyword, iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		value, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		// Each iteration gets its own environment so closures created in
+		// the body capture that iteration's value.
+		oldEnv := interpreter.environment
+		env := environment.NewEnvironment(&oldEnv)
+		env.Define(forIn.Name.Lexeme, value)
+		err = interpreter.executeBlock([]interfaces.Statement{forIn.Body}, env)
+		switch err.(type) {
+		case nil, continueSignal:
+		case breakSignal:
+			return nil, nil
+		default:
+			return nil, err
+		}
+
+		err = interpreter.budget.checkpoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// iterate returns a function yielding the successive values of a for-in
+// loop over iterable, with false once it is exhausted. Lists and map keys
+// are snapshotted when the loop starts and strings yield one rune at a
+// time. Any object, including a map, whose hasNext and next properties are
+// callable is instead driven through those methods.
+func (interpreter *Interpreter) iterate(keyword token.Token, iterable interface{}) (func() (interface{}, bool, error), error) {
+	if object, ok := iterable.(Object); ok {
+		hasNext, _ := object.Get(token.NewToken(token.IDENTIFIER, "hasNext", nil, keyword.Line))
+		next, _ := object.Get(token.NewToken(token.IDENTIFIER, "next", nil, keyword.Line))
+		_, hasNextOk := hasNext.(Callable)
+		_, nextOk := next.(Callable)
+		if hasNextOk && nextOk {
+			return func() (interface{}, bool, error) {
+				more, err := interpreter.call(keyword, hasNext, nil)
+				if err != nil || !functions.IsTruthy(more) {
+					return nil, false, err
+				}
+				value, err := interpreter.call(keyword, next, nil)
+				return value, err == nil, err
+			}, nil
+		}
+	}
+
+	var values []interface{}
+	switch iterable := iterable.(type) {
+	case *List:
+		values = make([]interface{}, len(iterable.Elements))
+		copy(values, iterable.Elements)
+	case *Map:
+		values = make([]interface{}, iterable.Len())
+		copy(values, iterable.Keys())
+	case string:
+		for _, r := range iterable {
+			values = append(values, string(r))
+		}
+	default:
+		return nil, errors.NewRuntimeError(keyword, "Can only iterate over lists, maps, strings and iterators.")
+	}
+
+	index := 0
+	return func() (interface{}, bool, error) {
+		if index >= len(values) {
+			return nil, false, nil
+		}
+		index++
+		return values[index-1], true, nil
+	}, nil
+}
+
+func (interpreter *Interpreter) VisitFunctionStatement(funStmt interfaces.Statement) (interface{}, error) {
+	functionStatement := funStmt.(statements.FunctionStatement)
+	function := NewFunction(functionStatement, interpreter.environment)
+	function.File = interpreter.file
+	interpreter.environment.Define(functionStatement.Name.Lexeme, function)
+	return nil, nil
+}
+
+func (interpreter *Interpreter) VisitImportStatement(importStmt interfaces.Statement) (interface{}, error) {
+	importStatement := importStmt.(statements.ImportStatement)
+	module, err := interpreter.importModule(importStatement.Keyword, importStatement.Path.Literal.(string))
+	if err != nil {
+		return nil, err
+	}
+	interpreter.environment.Define(importStatement.Name.Lexeme, module)
+	return nil, nil
+}
+
+func (interpreter *Interpreter) VisitExportStatement(exportStmt interfaces.Statement) (interface{}, error) {
+	exportStatement := exportStmt.(statements.ExportStatement)
+	err := interpreter.execute(exportStatement.Declaration)
+	if err != nil {
+		return nil, err
+	}
+	interpreter.exports[exportStatement.Name.Lexeme] = true
+	return nil, nil
+}
+
+func (interpreter *Interpreter) VisitReturnStatement(returnStmt interfaces.Statement) (interface{}, error) {
+	returnStatement := returnStmt.(statements.ReturnStatement)
+	var value interface{}
+	if returnStatement.Value != nil {
+		var err error
+		value, err = interpreter.evaluate(returnStatement.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, returnValue{Value: value}
+}
+
+func (interpreter *Interpreter) VisitBreakStatement(breakStmt interfaces.Statement) (interface{}, error) {
+	return nil, breakSignal{}
+}
+
+func (interpreter *Interpreter) VisitContinueStatement(continueStmt interfaces.Statement) (interface{}, error) {
+	return nil, continueSignal{}
+}
+
+func (interpreter *Interpreter) VisitThrowStatement(throwStmt interfaces.Statement) (interface{}, error) {
+	throwStatement := throwStmt.(statements.ThrowStatement)
+	value, err := interpreter.evaluate(throwStatement.Value)
+	if err != nil {
+		return nil, err
+	}
+	message := "Uncaught exception: " + interpreter.Stringify(value)
+	return nil, errors.NewThrownError(throwStatement.Keyword, message, value)
+}
+
+func (interpreter *Interpreter) VisitTryStatement(tryStmt interfaces.Statement) (interface{}, error) {
+	tryStatement := tryStmt.(statements.TryStatement)
+	oldEnv := interpreter.environment
+	err := interpreter.executeBlock(tryStatement.Body, environment.NewEnvironment(&oldEnv))
+
+	if runtimeError, ok := err.(errors.RuntimeError); ok && tryStatement.CatchBody != nil {
+		var caught interface{} = NewErrorObject(runtimeError)
+		if runtimeError.Thrown {
+			caught = runtimeError.Value
+		}
+		catchEnv := environment.NewEnvironment(&oldEnv)
+		catchEnv.Define(tryStatement.CatchName.Lexeme, caught)
+		err = interpreter.executeBlock(tryStatement.CatchBody, catchEnv)
+	}
+
+	if tryStatement.FinallyBody != nil {
+		finallyErr := interpreter.executeBlock(tryStatement.FinallyBody, environment.NewEnvironment(&oldEnv))
+		if finallyErr != nil {
+			return nil, finallyErr
+		}
+	}
+	return nil, err
+}
+
+func (interpreter Interpreter) VisitGetExpr(ge interfaces.Expr) (interface{}, error) {
+	get := ge.(expr.GetExpr)
+	object, err := interpreter.evaluate(get.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	if object, ok := object.(Object); ok {
+		return object.Get(get.Name)
+	}
+	if str, ok := object.(string); ok {
+		return stringMethod(get.Name, str)
+	}
+	return nil, errors.NewRuntimeError(get.Name, "Only instances have properties.")
+}
+
+func (interpreter Interpreter) VisitListExpr(le interfaces.Expr) (interface{}, error) {
+	list := le.(expr.ListExpr)
+	elements := make([]interface{}, 0, len(list.Elements))
+	for _, element := range list.Elements {
+		value, err := interpreter.evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return NewList(elements), nil
+}
+
+func (interpreter Interpreter) VisitMapExpr(me interfaces.Expr) (interface{}, error) {
+	mapExpr := me.(expr.MapExpr)
+	result := NewMap()
+	for i, keyExpr := range mapExpr.Keys {
+		key, err := interpreter.evaluate(keyExpr)
+		if err != nil {
+			return nil, err
+		}
+		err = checkKey(mapExpr.Brace, key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := interpreter.evaluate(mapExpr.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Set(key, value)
+	}
+	return result, nil
+}
+
+func (interpreter Interpreter) VisitInterpolationExpr(ie interfaces.Expr) (interface{}, error) {
+	interpolation := ie.(expr.InterpolationExpr)
+	var result strings.Builder
+	for _, part := range interpolation.Parts {
+		value, err := interpreter.evaluate(part)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(interpreter.Stringify(value))
+	}
+	return result.String(), nil
+}
+
+func (interpreter Interpreter) VisitIndexExpr(ie interfaces.Expr) (interface{}, error) {
+	indexExpr := ie.(expr.IndexExpr)
+	object, err := interpreter.evaluate(indexExpr.Object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := interpreter.evaluate(indexExpr.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	return interpreter.index(indexExpr.Bracket, object, index)
+}
+
+// index reads object[index] for an index expression at bracket.
+func (interpreter *Interpreter) index(bracket token.Token, object interface{}, index interface{}) (interface{}, error) {
+	switch object := object.(type) {
+	case *List:
+		i, err := toIndex(bracket, index, len(object.Elements), false)
+		if err != nil {
+			return nil, err
+		}
+		return object.Elements[i], nil
+	case *Map:
+		err := checkKey(bracket, index)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := object.Lookup(index)
+		if !ok {
+			return nil, errors.NewRuntimeError(bracket, "Undefined key '"+interpreter.Stringify(index)+"'.")
+		}
+		return value, nil
+	case string:
+		runes := []rune(object)
+		i, err := toIndex(bracket, index, len(runes), false)
+		if err != nil {
+			return nil, err
+		}
+		return string(runes[i]), nil
+	}
+	return nil, errors.NewRuntimeError(bracket, "Only lists, maps and strings can be indexed.")
+}
+
+func (interpreter Interpreter) VisitIndexSetExpr(ise interfaces.Expr) (interface{}, error) {
+	indexSet := ise.(expr.IndexSetExpr)
+	object, err := interpreter.evaluate(indexSet.Object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := interpreter.evaluate(indexSet.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	// A compound assignment reads the element through the object and index
+	// already evaluated, so their side effects happen once.
+	var current interface{}
+	if indexSet.Operator.TokenType != "" {
+		current, err = interpreter.index(indexSet.Bracket, object, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	value, err := interpreter.evaluate(indexSet.Value)
+	if err != nil {
+		return nil, err
+	}
+	if indexSet.Operator.TokenType != "" {
+		value, err = interpreter.binary(indexSet.Operator, current, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch object := object.(type) {
+	case string:
+		return nil, errors.NewRuntimeError(indexSet.Bracket, "Strings are immutable.")
+	case *List:
+		i, err := toIndex(indexSet.Bracket, index, len(object.Elements), false)
+		if err != nil {
+			return nil, err
+		}
+		object.Elements[i] = value
+		return value, nil
+	case *Map:
+		err := checkKey(indexSet.Bracket, index)
+		if err != nil {
+			return nil, err
+		}
+		object.Set(index, value)
+		return value, nil
+	}
+	return nil, errors.NewRuntimeError(indexSet.Bracket, "Only lists and maps can be indexed.")
+}
+
+func (interpreter Interpreter) VisitSliceExpr(se interfaces.Expr) (interface{}, error) {
+	slice := se.(expr.SliceExpr)
+	object, err := interpreter.evaluate(slice.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	// Strings are sliced by rune, so indices never split a character.
+	var length int
+	var runes []rune
+	switch object := object.(type) {
+	case *List:
+		length = len(object.Elements)
+	case string:
+		runes = []rune(object)
+		length = len(runes)
+	default:
+		return nil, errors.NewRuntimeError(slice.Bracket, "Only lists and strings can be sliced.")
+	}
+
+	start, end := 0, length
+	if slice.Start != nil {
+		value, err := interpreter.evaluate(slice.Start)
+		if err != nil {
+			return nil, err
+		}
+		start, err = toIndex(slice.Bracket, value, length, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if slice.End != nil {
+		value, err := interpreter.evaluate(slice.End)
+		if err != nil {
+			return nil, err
+		}
+		end, err = toIndex(slice.Bracket, value, length, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if start > end {
+		return nil, errors.NewRuntimeError(slice.Bracket, "Slice start must not be after its end.")
+	}
+
+	if _, ok := object.(string); ok {
+		return string(runes[start:end]), nil
+	}
+	elements := make([]interface{}, end-start)
+	copy(elements, object.(*List).Elements[start:end])
+	return NewList(elements), nil
+}
+
+func (interpreter Interpreter) VisitConditionalExpr(ce interfaces.Expr) (interface{}, error) {
+	conditional := ce.(expr.ConditionalExpr)
+	condition, err := interpreter.evaluate(conditional.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if functions.IsTruthy(condition) {
+		return interpreter.evaluate(conditional.ThenBranch)
+	}
+	return interpreter.evaluate(conditional.ElseBranch)
+}
+
+func (interpreter Interpreter) VisitCommaExpr(ce interfaces.Expr) (interface{}, error) {
+	comma := ce.(expr.CommaExpr)
+	var value interface{}
+	for _, expression := range comma.Expressions {
+		var err error
+		value, err = interpreter.evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (interpreter Interpreter) VisitLogicalExpr(le interfaces.Expr) (interface{}, error) {
+	logical := le.(expr.LogicalExpr)
+	left, err := interpreter.evaluate(logical.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	if logical.Operator.TokenType == token.OR {
+		if functions.IsTruthy(left) {
+			return left, nil
+		}
+	} else if !functions.IsTruthy(left) {
+		return left, nil
+	}
+
+	return interpreter.evaluate(logical.Right)
+}
+
+func (interpreter Interpreter) VisitFunctionExpr(fe interfaces.Expr) (interface{}, error) {
+	function := fe.(expr.FunctionExpr)
+	declaration := statements.NewFunctionStatement(function.Keyword, function.Params, function.Body)
+	closure := NewFunction(declaration, interpreter.environment)
+	closure.File = interpreter.file
+	return closure, nil
+}
+
+func (interpreter Interpreter) VisitCallExpr(ce interfaces.Expr) (interface{}, error) {
+	call := ce.(expr.CallExpr)
+	callee, err := interpreter.evaluate(call.Callee)
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments []interface{}
+	for _, argument := range call.Arguments {
+		value, err := interpreter.evaluate(argument)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+
+	return interpreter.call(call.Paren, callee, arguments)
+}
+
+// call invokes callee on behalf of the expression at paren, enforcing arity
+// and the call depth limit and recording a stack frame for runtime errors.
+func (interpreter *Interpreter) call(paren token.Token, callee interface{}, arguments []interface{}) (interface{}, error) {
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, errors.NewRuntimeError(paren, "Can only call functions and classes.")
+	}
+
+	if function.Arity() >= 0 && len(arguments) != function.Arity() {
+		message := fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments))
+		return nil, errors.NewRuntimeError(paren, message)
+	}
+
+	err := interpreter.budget.enterCall(paren)
+	if err != nil {
+		return nil, err
+	}
+	defer interpreter.budget.exitCall()
+
+	value, err := function.Call(interpreter, arguments)
+	if runtimeError, ok := err.(errors.RuntimeError); ok {
+		// Natives raise errors without a token and get no frame of their
+		// own, so they are reported at the call site.
+		if _, isNative := function.(*NativeFunction); isNative {
+			if runtimeError.Token.Line == 0 {
+				runtimeError.Token = paren
+			}
+		} else {
+			frame := errors.StackFrame{Function: callableName(function), Line: paren.Line}
+			if declared, ok := function.(*Function); ok {
+				frame.File = declared.File
+			}
+			runtimeError.Stack = append(runtimeError.Stack, frame)
+		}
+		return nil, runtimeError
+	}
+	return value, err
+}
+
 // VisitBlockStatement implements interfaces.StatementVisitor.
 func (interpreter *Interpreter) VisitBlockStatement(blockStmt interfaces.Statement) (interface{}, error) {
 	blockStatement := blockStmt.(statements.BlockStatement)
@@ -55,23 +620,11 @@ func (interpreter Interpreter) VisitAssignExpr(ae interfaces.Expr) (interface{},
 	if err != nil {
 		return nil, err
 	}
-	until := true
-	for until {
-		switch v := value.(type) {
-		case interfaces.Expr:
-			value, err = v.Accept(interpreter)
-			if err != nil {
-				return nil, err
-			}
-		default:
-			until = false
-		}
-	}
 	err = interpreter.environment.Assign(assignExpr.Name, value)
 	if err != nil {
 		return nil, err
 	}
-	return assignExpr.Value, nil
+	return value, nil
 }
 
 func (interpreter Interpreter) VisitVarExpr(v interfaces.Expr) (interface{}, error) {
@@ -99,7 +652,7 @@ func (interpreter Interpreter) VisitVarStatement(varStmt interfaces.Statement) (
 
 func checkNumberOperand(t token.Token, operands ...interface{}) error {
 	for _, operand := range operands {
-		if functions.TypeOf(operand) != "float64" {
+		if !isNumber(operand) {
 			return errors.NewRuntimeError(t, "Operand must be a number.")
 		}
 	}
@@ -121,69 +674,91 @@ func (interpreter Interpreter) VisitBinaryExpr(b interfaces.Expr) (interface{},
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.binary(binary.Operator, left, right)
+}
 
-	switch binary.Operator.TokenType {
+// binary applies a binary operator to evaluated operands.
+func (interpreter *Interpreter) binary(operator token.Token, left interface{}, right interface{}) (interface{}, error) {
+	switch operator.TokenType {
 	case token.BANG:
 		return functions.IsTruthy(right), nil
 	case token.MINUS:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) - right.(float64), nil
+		return arithmetic(operator, left, right)
 	case token.PLUS:
 		isSame := functions.TypeOf(left) == functions.TypeOf(right)
-		isString, isFloat := functions.TypeOf(left) == "string", functions.TypeOf(left) == "float64"
+		isString := functions.TypeOf(left) == "string"
 
 		if isSame && isString {
 			return left.(string) + right.(string), nil
 		}
 
-		if isSame && isFloat {
-			return left.(float64) + right.(float64), nil
+		if isNumber(left) && isNumber(right) {
+			return arithmetic(operator, left, right)
 		}
-		return nil, errors.NewRuntimeError(binary.Operator, "Operands must be two numbers or two strings.")
+		return nil, errors.NewRuntimeError(operator, "Operands must be two numbers or two strings.")
 	case token.SLASH:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) / right.(float64), nil
+		return arithmetic(operator, left, right)
 	case token.STAR:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(operator, left, right)
+	case token.PERCENT:
+		err := checkNumberOperand(operator, right, left)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(operator, left, right)
+	case token.STAR_STAR:
+		err := checkNumberOperand(operator, right, left)
+		if err != nil {
+			return nil, err
+		}
+		return power(operator, left, right)
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) * right.(float64), nil
+		return bitwise(operator, left, right)
 	case token.GREATER:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) > right.(float64), nil
+		return compare(operator.TokenType, left, right), nil
 	case token.GREATER_EQUAL:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) >= right.(float64), nil
+		return compare(operator.TokenType, left, right), nil
 	case token.LESS:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) < right.(float64), nil
+		return compare(operator.TokenType, left, right), nil
 	case token.LESS_EQUAL:
-		err := checkNumberOperand(binary.Operator, right, left)
+		err := checkNumberOperand(operator, right, left)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) <= right.(float64), nil
+		return compare(operator.TokenType, left, right), nil
 	case token.BANG_EQUAL:
 		// fmt.Println("!=", left != right)
-		return left != right, nil
+		return !isEqual(left, right), nil
 	case token.EQUAL_EQUAL:
-		return left == right, nil
+		return isEqual(left, right), nil
 	}
 
 	return nil, nil
@@ -215,7 +790,15 @@ func (interpreter Interpreter) VisitUnaryExpr(u interfaces.Expr) (interface{}, e
 		if err != nil {
 			return nil, err
 		}
-		return -right.(float64), nil
+		return negate(right), nil
+	}
+
+	if unary.Operator.TokenType == token.TILDE {
+		err := checkNumberOperand(unary.Operator, right)
+		if err != nil {
+			return nil, err
+		}
+		return complement(unary.Operator, right)
 	}
 	return nil, nil
 }
@@ -236,10 +819,20 @@ func (interpreter Interpreter) VisitExpressionStatement(exprStmt interfaces.Stat
 }
 
 func (interpreter *Interpreter) Stringify(obj interface{}) string {
+	return interpreter.stringify(obj, nil)
+}
+
+// stringify is Stringify, printing a list or map already in visiting, the
+// collections being printed, as [...] or {...} so cycles terminate.
+func (interpreter *Interpreter) stringify(obj interface{}, visiting map[interface{}]bool) string {
 	if obj == nil {
 		return "nil"
 	}
 
+	if integer, ok := obj.(int64); ok {
+		return strconv.FormatInt(integer, 10)
+	}
+
 	if functions.TypeOf(obj) == "float64" {
 		result := functions.FormatWithFixedPrecision(obj.(float64))
 
@@ -254,17 +847,67 @@ func (interpreter *Interpreter) Stringify(obj interface{}) string {
 	case expr.LiteralExpr:
 		v := obj.Literal
 		return fmt.Sprintf("%v", v)
+	case *List:
+		if visiting[obj] {
+			return "[...]"
+		}
+		if visiting == nil {
+			visiting = map[interface{}]bool{}
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+
+		elements := make([]string, len(obj.Elements))
+		for i, element := range obj.Elements {
+			elements[i] = interpreter.stringify(element, visiting)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *Map:
+		if visiting[obj] {
+			return "{...}"
+		}
+		if visiting == nil {
+			visiting = map[interface{}]bool{}
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+
+		entries := make([]string, obj.Len())
+		for i, key := range obj.Keys() {
+			value, _ := obj.Lookup(key)
+			entries[i] = interpreter.stringify(key, visiting) + ": " + interpreter.stringify(value, visiting)
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
 	}
 
 	return fmt.Sprintf("%v", obj)
 }
 
 func (interpreter *Interpreter) execute(statement interfaces.Statement) error {
-	_, err := statement.Accept(interpreter)
+	err := interpreter.budget.step()
+	if err != nil {
+		return err
+	}
+	_, err = statement.Accept(interpreter)
 	return err
 }
 
+// SetLimits bounds every following call to Interpret. Exceeding a limit
+// stops the script with an errors.LimitError.
+func (interpreter *Interpreter) SetLimits(limits Limits) {
+	interpreter.budget.limits = limits
+}
+
 func (interpreter *Interpreter) Interpret(statements []interfaces.Statement) error {
+	return interpreter.InterpretContext(context.Background(), statements)
+}
+
+// InterpretContext runs statements until they finish or ctx is done, in
+// which case it returns an errors.CancellationError.
+func (interpreter *Interpreter) InterpretContext(ctx context.Context, statements []interfaces.Statement) error {
+	cancel := interpreter.budget.start(ctx)
+	defer cancel()
+
 	for _, statement := range statements {
 		err := interpreter.execute(statement)
 		if err != nil {
@@ -284,8 +927,56 @@ func (interpreter *Interpreter) Interpret(statements []interfaces.Statement) err
 // }
 
 func NewInterpreter() Interpreter {
+	globals := environment.NewEnvironment(nil)
+	globals.Define("clock", NewNativeFunction("clock", 0, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		return float64(interpreter.clock.Now().UnixMilli()) / 1000.0, nil
+	}))
+	globals.Define("len", NewNativeFunction("len", 1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		switch value := arguments[0].(type) {
+		case *List:
+			return int64(len(value.Elements)), nil
+		case *Map:
+			return int64(value.Len()), nil
+		case string:
+			return int64(utf8.RuneCountInString(value)), nil
+		}
+		return nil, errors.NewRuntimeError(token.NewTokenNil(), "Argument to len() must be a list, map or string.")
+	}))
+	// assert raises a runtime error, with the optional message, when its
+	// condition is falsey.
+	globals.Define("assert", NewNativeFunction("assert", -1, func(interpreter *Interpreter, arguments []interface{}) (interface{}, error) {
+		err := checkArgumentRange(arguments, 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		if functions.IsTruthy(arguments[0]) {
+			return nil, nil
+		}
+		if len(arguments) == 2 {
+			return nil, errors.NewRuntimeError(token.NewTokenNil(), "Assertion failed: "+interpreter.Stringify(arguments[1]))
+		}
+		return nil, errors.NewRuntimeError(token.NewTokenNil(), "Assertion failed.")
+	}))
+	defineNumberNatives(globals)
+	globals.Define("math", newMathModule())
+	defineStringNatives(globals)
+	globals.Define("io", newIOModule())
+	globals.Define("json", newJSONModule())
+	globals.Define("re", newRegexModule())
+	globals.Define("time", newTimeModule())
+	globals.Define("os", newOSModule())
+
 	return Interpreter{
-		environment: environment.NewEnvironment(nil),
+		environment: globals,
+		globals:     &globals,
+		budget:      &budget{},
+		exports:     map[string]bool{},
+		modules:     newModules(),
+		files:       newFiles(),
+		patterns:    newPatterns(),
+		random:      newRandom(),
+		clock:       systemClock{},
+		system:      &system{},
 	}
 }
 
@@ -304,19 +995,149 @@ func (printer *AstPrinter) VisitBlockStatement(blockStmt interfaces.Statement) (
 	panic("unimplemented")
 }
 
-// VisitAssignExpr implements interfaces.Visitor.
-func (printer *AstPrinter) VisitAssignExpr(ae interfaces.Expr) (interface{}, error) {
+// VisitIfStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitIfStatement(ifStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitWhileStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitWhileStatement(whileStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitForInStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitForInStatement(forInStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitFunctionStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitFunctionStatement(funStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitImportStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitImportStatement(importStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitExportStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitExportStatement(exportStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitReturnStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitReturnStatement(returnStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitBreakStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitBreakStatement(breakStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitContinueStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitContinueStatement(continueStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitThrowStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitThrowStatement(throwStmt interfaces.Statement) (interface{}, error) {
+	panic("unimplemented")
+}
+
+// VisitTryStatement implements interfaces.StatementVisitor.
+func (printer *AstPrinter) VisitTryStatement(tryStmt interfaces.Statement) (interface{}, error) {
 	panic("unimplemented")
 }
 
+func (printer *AstPrinter) VisitGetExpr(ge interfaces.Expr) (interface{}, error) {
+	get := ge.(expr.GetExpr)
+	return printer.parenthesize("."+get.Name.Lexeme, get.Object)
+}
+
+func (printer *AstPrinter) VisitListExpr(le interfaces.Expr) (interface{}, error) {
+	list := le.(expr.ListExpr)
+	return printer.parenthesize("list", list.Elements...)
+}
+
+func (printer *AstPrinter) VisitMapExpr(me interfaces.Expr) (interface{}, error) {
+	mapExpr := me.(expr.MapExpr)
+	var entries []interfaces.Expr
+	for i, key := range mapExpr.Keys {
+		entries = append(entries, key, mapExpr.Values[i])
+	}
+	return printer.parenthesize("map", entries...)
+}
+
+func (printer *AstPrinter) VisitInterpolationExpr(ie interfaces.Expr) (interface{}, error) {
+	interpolation := ie.(expr.InterpolationExpr)
+	return printer.parenthesize("interpolate", interpolation.Parts...)
+}
+
+func (printer *AstPrinter) VisitIndexExpr(ie interfaces.Expr) (interface{}, error) {
+	index := ie.(expr.IndexExpr)
+	return printer.parenthesize("index", index.Object, index.Index)
+}
+
+func (printer *AstPrinter) VisitIndexSetExpr(ise interfaces.Expr) (interface{}, error) {
+	indexSet := ise.(expr.IndexSetExpr)
+	return printer.parenthesize(indexSet.Operator.Lexeme+"=", expr.NewIndexExpr(indexSet.Object, indexSet.Bracket, indexSet.Index), indexSet.Value)
+}
+
+func (printer *AstPrinter) VisitSliceExpr(se interfaces.Expr) (interface{}, error) {
+	slice := se.(expr.SliceExpr)
+	bounds := []interfaces.Expr{slice.Object}
+	for _, bound := range []interfaces.Expr{slice.Start, slice.End} {
+		if bound == nil {
+			bound = expr.NewLiteral(nil)
+		}
+		bounds = append(bounds, bound)
+	}
+	return printer.parenthesize("slice", bounds...)
+}
+
+func (printer *AstPrinter) VisitConditionalExpr(ce interfaces.Expr) (interface{}, error) {
+	conditional := ce.(expr.ConditionalExpr)
+	return printer.parenthesize("?:", conditional.Condition, conditional.ThenBranch, conditional.ElseBranch)
+}
+
+func (printer *AstPrinter) VisitCommaExpr(ce interfaces.Expr) (interface{}, error) {
+	comma := ce.(expr.CommaExpr)
+	return printer.parenthesize(",", comma.Expressions...)
+}
+
+func (printer *AstPrinter) VisitLogicalExpr(le interfaces.Expr) (interface{}, error) {
+	logical := le.(expr.LogicalExpr)
+	return printer.parenthesize(logical.Operator.Lexeme, logical.Left, logical.Right)
+}
+
+func (printer *AstPrinter) VisitFunctionExpr(fe interfaces.Expr) (interface{}, error) {
+	function := fe.(expr.FunctionExpr)
+	params := make([]string, len(function.Params))
+	for i, param := range function.Params {
+		params[i] = param.Lexeme
+	}
+	return "(fun (" + strings.Join(params, " ") + "))", nil
+}
+
+func (printer *AstPrinter) VisitCallExpr(ce interfaces.Expr) (interface{}, error) {
+	call := ce.(expr.CallExpr)
+	return printer.parenthesize("call", append([]interfaces.Expr{call.Callee}, call.Arguments...)...)
+}
+
+func (printer *AstPrinter) VisitAssignExpr(ae interfaces.Expr) (interface{}, error) {
+	assign := ae.(expr.AssignExpr)
+	return printer.parenthesize("= "+assign.Name.Lexeme, assign.Value)
+}
+
 // VisitVarStatement implements interfaces.StatementVisitor.
 func (printer *AstPrinter) VisitVarStatement(varStmt interfaces.Statement) (interface{}, error) {
 	panic("unimplemented")
 }
 
-// VisitVarExpr implements interfaces.Visitor.
 func (printer *AstPrinter) VisitVarExpr(v interfaces.Expr) (interface{}, error) {
-	panic("unimplemented")
+	varExpr := v.(expr.VarExpr)
+	return varExpr.Token.Lexeme, nil
 }
 
 func (printer *AstPrinter) VisitExpressionStatement(exprStmt interfaces.Statement) (interface{}, error) {
@@ -374,6 +1195,11 @@ func (printer *AstPrinter) VisitLiteralExpr(l interfaces.Expr) (interface{}, err
 		value := literal.Literal.(float64)
 		formattedValue := functions.FormatWithFixedPrecision(value)
 		return formattedValue, nil
+	case int64:
+		value := literal.Literal.(int64)
+		return strconv.FormatInt(value, 10), nil
+	case *big.Int, decimal.Decimal:
+		return fmt.Sprintf("%v", literal.Literal), nil
 	case string:
 		value := literal.Literal.(string)
 		return value, nil